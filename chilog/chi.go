@@ -0,0 +1,81 @@
+package chilog
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goccha/logging/httplog"
+	"github.com/goccha/logging/log"
+	"github.com/rs/zerolog"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option is the option of TraceRequest.
+type Option = httplog.Option
+
+// WithDump enables or disables dumping of the request headers.
+// The default is false.
+func WithDump(dump bool, opts ...httplog.DumpOption) Option {
+	return httplog.WithDump(dump, opts...)
+}
+
+// WithResponseRequestId enables or disables writing the effective request ID to the response header.
+// The default is false.
+func WithResponseRequestId(enable bool) Option {
+	return httplog.WithResponseRequestId(enable)
+}
+
+// SetLogger changes the logger used by chilog.
+// The default is log.Named("httplog").
+func SetLogger(l *log.Logger) {
+	httplog.SetLogger(l)
+}
+
+// TraceRequest does what httplog.TraceRequest does and sets the route pattern matched by chi to the span.
+func TraceRequest(options ...Option) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		h := httplog.TraceRequest(next, options...)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+			if pattern := routePattern(r); pattern != "" {
+				trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRoute(pattern))
+			}
+		})
+	}
+}
+
+// AccessLog writes an access log after the handler returns.
+// The route pattern matched by chi is written as httpRequest.route.
+func AccessLog(f ...func(r *http.Request, w httplog.ResponseWriter, e *zerolog.Event) *zerolog.Event) func(next http.Handler) http.Handler {
+	filters := make([]httplog.Filter, 0, len(f))
+	for _, filter := range f {
+		filters = append(filters, filter)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := httplog.NewResponseWriter(w)
+			// Start timer
+			start := time.Now()
+			// Process request
+			next.ServeHTTP(rw, r)
+			// Stop timer
+			latency := time.Since(start)
+			httplog.JsonLog(r, rw, func(r *http.Request, e *zerolog.Event) {
+				e.Str("latency", fmt.Sprintf("%vs", latency.Seconds()))
+				if pattern := routePattern(r); pattern != "" {
+					e.Str("route", pattern)
+				}
+			}, filters...)
+		})
+	}
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package chilog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/goccha/logging/httplog"
+	"github.com/goccha/logging/log"
+	"github.com/rs/zerolog"
+)
+
+func TestAccessLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("httplog"))
+
+	r := chi.NewRouter()
+	r.Use(TraceRequest(WithDump(true)), AccessLog(func(r *http.Request, w httplog.ResponseWriter, e *zerolog.Event) *zerolog.Event {
+		if w.Status() == http.StatusNoContent {
+			return nil
+		}
+		return e
+	}))
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "0" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var m struct {
+		HttpRequest struct {
+			Status       int    `json:"status"`
+			ResponseSize int    `json:"responseSize"`
+			Route        string `json:"route"`
+		} `json:"httpRequest"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.HttpRequest.Status != http.StatusOK || m.HttpRequest.ResponseSize != 5 || m.HttpRequest.Route != "/users/{id}" {
+		t.Errorf("log = %s", lines[len(lines)-1])
+	}
+
+	buf.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/0", nil))
+	if strings.Contains(buf.String(), "httpRequest") {
+		t.Errorf("filtered log was written: %s", buf.String())
+	}
+}
@@ -0,0 +1,84 @@
+package echolog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goccha/http-constants/pkg/headers"
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+var logger = log.Named("echolog")
+
+// SetLogger changes the logger used by echolog.
+// The default is log.Named("echolog").
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+// AccessLog writes an access log after the handler returns.
+// An error returned by the handler is passed to echo's HTTPErrorHandler first,
+// so that the logged status is the one sent to the client, and is logged as the message.
+func AccessLog(f ...func(c echo.Context, e *zerolog.Event) *zerolog.Event) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// Start timer
+			start := time.Now()
+			// Process request
+			err := next(c)
+			if err != nil && !c.Response().Committed {
+				c.Error(err)
+			}
+			// Stop timer
+			latency := time.Since(start)
+			JsonLog(c, err, func(c echo.Context, e *zerolog.Event) {
+				e.Str("latency", fmt.Sprintf("%vs", latency.Seconds()))
+			}, f...)
+			return err
+		}
+	}
+}
+
+func JsonLog(c echo.Context, err error, f func(c echo.Context, e *zerolog.Event), filters ...func(c echo.Context, e *zerolog.Event) *zerolog.Event) {
+	req := c.Request()
+	ctx := req.Context()
+	ua := req.Header.Get(headers.UserAgent)
+	requestUrl := req.URL.String()
+	if req.URL.Scheme == "" {
+		requestUrl = fmt.Sprintf("%s://%s%s", c.Scheme(), req.Host, requestUrl)
+	}
+	res := c.Response()
+	dict := zerolog.Dict().
+		Int("status", res.Status).Str("remoteIp", tracing.ClientIP(req)).
+		Str("userAgent", ua).
+		Str("requestMethod", req.Method).Str("requestUrl", requestUrl).
+		Str("protocol", req.Proto).Int64("requestSize", req.ContentLength).
+		Int64("responseSize", res.Size)
+	if f != nil {
+		f(c, dict)
+	}
+	e := log.EmbedObject(ctx, logger.Info(ctx).Dict("httpRequest", dict))
+	for _, filter := range filters {
+		if e = filter(c, e); e == nil {
+			return
+		}
+	}
+	if err != nil {
+		e.Msg(errorMessage(err))
+	} else {
+		e.Send()
+	}
+}
+
+func errorMessage(err error) string {
+	if he, ok := err.(*echo.HTTPError); ok {
+		if he.Internal != nil {
+			return fmt.Sprintf("%v: %v", he.Message, he.Internal)
+		}
+		return fmt.Sprint(he.Message)
+	}
+	return err.Error()
+}
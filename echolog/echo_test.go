@@ -0,0 +1,67 @@
+package echolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccha/logging/log"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+func TestAccessLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("echolog"))
+
+	e := echo.New()
+	e.Use(TraceRequest(WithDump(true)), AccessLog(func(c echo.Context, e *zerolog.Event) *zerolog.Event {
+		return e.Str("route", c.Path())
+	}))
+	e.GET("/users/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+		return c.String(http.StatusOK, "hello")
+	})
+
+	tests := []struct {
+		path    string
+		status  int
+		size    int
+		message string
+	}{
+		{path: "/users/1", status: http.StatusOK, size: 5},
+		{path: "/users/0", status: http.StatusNotFound, size: 29, message: "user not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			buf.Reset()
+			w := httptest.NewRecorder()
+			e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("code = %d, want %d", w.Code, tt.status)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			var m struct {
+				Message     string `json:"message"`
+				Route       string `json:"route"`
+				HttpRequest struct {
+					Status       int `json:"status"`
+					ResponseSize int `json:"responseSize"`
+				} `json:"httpRequest"`
+			}
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &m); err != nil {
+				t.Fatal(err)
+			}
+			if m.HttpRequest.Status != tt.status || m.HttpRequest.ResponseSize != tt.size ||
+				m.Message != tt.message || m.Route != "/users/:id" {
+				t.Errorf("log = %s", lines[len(lines)-1])
+			}
+		})
+	}
+}
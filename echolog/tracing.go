@@ -0,0 +1,83 @@
+package echolog
+
+import (
+	"github.com/goccha/logging/httplog"
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing"
+	"github.com/goccha/logging/tracing/tracelog"
+	"github.com/labstack/echo/v4"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Option func(o *option)
+
+// WithDump enables or disables dumping of the request headers.
+// All values of the headers are written under headers, and the values of masking.RedactHeaders are redacted.
+// The default is false.
+func WithDump(dump bool, opts ...httplog.DumpOption) Option {
+	return func(o *option) {
+		o.dump = dump
+		o.headers = httplog.NewHeaderDump(opts...)
+	}
+}
+
+// WithResponseRequestId enables or disables writing the effective request ID to the response header
+// named by tracelog.Config.RequestIdHeaderName. The default is false.
+func WithResponseRequestId(enable bool) Option {
+	return func(o *option) {
+		o.responseRequestId = enable
+	}
+}
+
+type option struct {
+	dump              bool
+	headers           *httplog.HeaderDump
+	responseRequestId bool
+}
+
+func (o *option) apply(options ...Option) *option {
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+func TraceRequest(options ...Option) echo.MiddlewareFunc {
+	o := new(option).apply(options...)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+			span := trace.SpanFromContext(ctx)
+			// echo reads the scheme from the proxy headers as well
+			span.SetAttributes(append(tracing.RequestAttributes(req, ""), semconv.URLScheme(c.Scheme()))...)
+			c.SetRequest(req.WithContext(tracelog.WithContext(ctx, req)))
+			if o.responseRequestId {
+				if id := tracelog.RequestId(c.Request().Context()); id != "" {
+					c.Response().Header().Set(tracelog.TraceConfig().RequestIdHeaderName(), id)
+				}
+			}
+			if o.dump {
+				ctx := c.Request().Context()
+				o.headers.Log(logger.Debug(ctx), req.Header, "dumpHeaders")
+				log.Dump(ctx, logger.Debug(ctx)).Msg("dump")
+			}
+			err := next(c)
+			if err != nil && !c.Response().Committed {
+				c.Error(err)
+			}
+			if o.dump && o.headers.Response() {
+				o.headers.Log(logger.Debug(c.Request().Context()), c.Response().Header(), "dumpResponseHeaders")
+			}
+			if path := c.Path(); path != "" {
+				span.SetAttributes(semconv.HTTPRoute(path))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(c.Response().Status))
+			if size := c.Response().Size; size > 0 {
+				span.SetAttributes(semconv.HTTPResponseBodySize(int(size)))
+			}
+			return err
+		}
+	}
+}
@@ -47,6 +47,7 @@ func New() func(ctx context.Context, req *http.Request) tracing.Tracing {
 	}
 }
 
+// getRequestId does not apply the RequestIdGenerator of tracing/tracelog. Use extensions/xray to generate missing request IDs.
 func getRequestId(ctx context.Context, req *http.Request) string {
 	var requestId string
 	if strings.HasPrefix(awsEnv, "AWS_Lambda_") { // Lambda環境の場合
@@ -100,6 +101,10 @@ type TracingContext struct {
 	Service   string
 }
 
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
 	span := trace.SpanFromContext(ctx)
 	if span != nil {
@@ -61,6 +61,10 @@ type TracingContext struct {
 	Producer  string
 }
 
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
 	span := trace.SpanFromContext(ctx)
 	if span != nil {
@@ -51,6 +51,12 @@ func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog
 		Str("request_id", tc.RequestID)
 }
 
+// GetRequestId returns the request ID.
+// Deprecated: Use cloudtrace.TracingContext.GetRequestId instead.
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 // WithTrace adds tracing information to the log event.
 // Deprecated: Use cloudtrace.TracingContext.WithTrace instead.
 func (tc *TracingContext) WithTrace(ctx context.Context, event *zerolog.Event) *zerolog.Event {
@@ -0,0 +1,24 @@
+package datadog
+
+import (
+	"fmt"
+
+	"github.com/goccha/logging/log"
+	"github.com/rs/zerolog"
+)
+
+const (
+	ErrorMessageKey = "error.message"
+	ErrorKindKey    = "error.kind"
+	ErrorStackKey   = "error.stack"
+)
+
+// ErrorFormatter writes errors to the attributes recognised by Datadog Error Tracking.
+func ErrorFormatter(e *zerolog.Event, err error, chain log.ErrorChain, stack string) *zerolog.Event {
+	kind := "error"
+	if len(chain) > 0 {
+		kind = fmt.Sprintf("%T", chain[len(chain)-1])
+	}
+	return e.Str(ErrorMessageKey, err.Error()).Str(ErrorKindKey, kind).
+		Array("errors", chain).Str(ErrorStackKey, stack)
+}
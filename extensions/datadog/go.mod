@@ -3,7 +3,7 @@ module github.com/goccha/logging/extensions/datadog
 go 1.24.0
 
 require (
-	github.com/goccha/logging v0.3.0
+	github.com/goccha/logging v0.4.0
 	github.com/rs/zerolog v1.34.0
 	go.opentelemetry.io/otel/trace v1.38.0
 )
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/goccha/logging/log"
 	"github.com/goccha/logging/tracing"
 	"github.com/goccha/logging/tracing/tracelog"
 	"github.com/rs/zerolog"
@@ -36,6 +37,7 @@ func Version(version string) tracelog.Option {
 func Setup(opt ...tracelog.Option) {
 	opt = append(opt, tracelog.WithNewFunc(New()))
 	tracelog.Setup(opt...)
+	log.SetErrorFormatter(ErrorFormatter)
 }
 
 func New() func(ctx context.Context, req *http.Request) tracing.Tracing {
@@ -70,6 +72,10 @@ type TracingContext struct {
 	Version   string
 }
 
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
 	span := trace.SpanFromContext(ctx)
 	if span != nil {
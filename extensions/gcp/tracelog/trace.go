@@ -28,6 +28,7 @@ func Setup() {
 }
 
 // New
+// The RequestIdGenerator of tracing/tracelog is not applied. Use extensions/cloudtrace to generate missing request IDs.
 // Deprecated: cloudtrace/tracelog.New instead.
 func New() func(ctx context.Context, req *http.Request) tracing.Tracing {
 	return func(ctx context.Context, req *http.Request) tracing.Tracing {
@@ -60,6 +61,10 @@ type TracingContext struct {
 	Producer  string
 }
 
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
 	span := trace.SpanFromContext(ctx)
 	if span != nil {
@@ -27,6 +27,8 @@ type RequestIdFunc func(ctx context.Context, req *http.Request) string
 
 var _config = &Config{}
 
+// GetRequestId returns the request ID of the request.
+// This package has its own configuration, so the RequestIdGenerator of tracing/tracelog is not applied.
 func (c *Config) GetRequestId(ctx context.Context, req *http.Request) string {
 	if c.RequestIdFunc != nil {
 		return c.RequestIdFunc(ctx, req)
@@ -7,7 +7,7 @@ require (
 	github.com/awslabs/aws-lambda-go-api-proxy v0.16.2
 	github.com/goccha/envar v0.3.6
 	github.com/goccha/http-constants v0.1.2
-	github.com/goccha/logging v0.3.0
+	github.com/goccha/logging v0.4.0
 	github.com/rs/zerolog v1.34.0
 	go.opentelemetry.io/contrib/detectors/aws/lambda v0.63.0
 	go.opentelemetry.io/contrib/propagators/aws v1.38.0
@@ -44,10 +44,10 @@ func RequestId() tracelog.RequestIdFunc {
 
 func getRequestId(ctx context.Context, req *http.Request) string {
 	var requestId string
+	config := tracelog.TraceConfig()
 	if isLambda { // Lambda環境の場合
 		requestId = getLambdaRequestId(ctx)
 	} else {
-		config := tracelog.TraceConfig()
 		if config.RequestIdHeader != "" {
 			requestId = req.Header.Get(config.RequestIdHeader)
 		}
@@ -58,6 +58,9 @@ func getRequestId(ctx context.Context, req *http.Request) string {
 	if requestId == "" {
 		requestId = req.Header.Get(headers.RequestID)
 	}
+	if requestId == "" && config.RequestIdGenerator != nil {
+		requestId = config.RequestIdGenerator(ctx, req)
+	}
 	return requestId
 }
 
@@ -95,6 +98,10 @@ type TracingContext struct {
 	Service   string
 }
 
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
 	spanCtx := trace.SpanFromContext(ctx).SpanContext()
 	log = log.Str("trace_id", spanCtx.TraceID().String()).Str("span_id", spanCtx.SpanID().String()).
@@ -49,10 +49,10 @@ func AwsRequestId() tracelog.RequestIdFunc {
 
 func getRequestId(ctx context.Context, req *http.Request) string {
 	var requestId string
+	config := tracelog.TraceConfig()
 	if isLambda { // Lambda環境の場合
 		requestId = getLambdaRequestId(ctx)
 	} else {
-		config := tracelog.TraceConfig()
 		if config.RequestIdHeader != "" {
 			requestId = req.Header.Get(config.RequestIdHeader)
 		}
@@ -63,6 +63,9 @@ func getRequestId(ctx context.Context, req *http.Request) string {
 	if requestId == "" {
 		requestId = req.Header.Get(headers.RequestID)
 	}
+	if requestId == "" && config.RequestIdGenerator != nil {
+		requestId = config.RequestIdGenerator(ctx, req)
+	}
 	return requestId
 }
 
@@ -0,0 +1,178 @@
+package ginlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccha/logging/masking"
+	"github.com/rs/zerolog"
+)
+
+// BodyCaptureKey はルート単位でボディの記録を有効にするginコンテキストのキーです。
+const BodyCaptureKey = "github.com/goccha/logging/ginlog/captureBody"
+
+// CaptureBody はBodyOptInを指定したAccessLogWithでボディを記録するルートに設定するハンドラーです。
+func CaptureBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(BodyCaptureKey, true)
+	}
+}
+
+type BodyOption func(b *bodyCapture)
+
+// BodyLimit は記録するボディの最大サイズを設定します。超えた部分は切り捨てられます。
+// デフォルトは64KiBです。
+func BodyLimit(limit int) BodyOption {
+	return func(b *bodyCapture) {
+		if limit > 0 {
+			b.redactor.SetMaxBodySize(limit)
+		}
+	}
+}
+
+// BodyContentTypes は記録するボディのメディアタイプを設定します。"text/*" のようにサブタイプを省略できます。
+// デフォルトは application/json と application/x-www-form-urlencoded です。
+func BodyContentTypes(types ...string) BodyOption {
+	return func(b *bodyCapture) {
+		b.contentTypes = types
+	}
+}
+
+// BodyMasking はJSONとフォームのボディをpでマスクします。
+func BodyMasking(p *masking.Processor) BodyOption {
+	return func(b *bodyCapture) {
+		b.redactor.SetProcessor(p)
+	}
+}
+
+// BodyOptIn はBodyCaptureKeyが設定されたルートだけボディを記録します。
+func BodyOptIn() BodyOption {
+	return func(b *bodyCapture) {
+		b.optIn = true
+	}
+}
+
+type bodyCapture struct {
+	redactor     *masking.Redactor
+	contentTypes []string
+	optIn        bool
+}
+
+func newBodyCapture(opts ...BodyOption) *bodyCapture {
+	b := &bodyCapture{
+		redactor:     masking.NewRedactor(),
+		contentTypes: []string{"application/json", "application/x-www-form-urlencoded"},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// bodyRecorder はリクエストとレスポンスのボディの先頭を記録します。
+type bodyRecorder struct {
+	*bodyCapture
+	req    *bodyReader
+	writer *bodyWriter
+}
+
+func (b *bodyCapture) start(c *gin.Context) *bodyRecorder {
+	r := &bodyRecorder{bodyCapture: b}
+	limit := b.redactor.MaxBodySize()
+	if c.Request.Body != nil {
+		r.req = &bodyReader{ReadCloser: c.Request.Body, limit: limit}
+		c.Request.Body = r.req
+	}
+	r.writer = &bodyWriter{ResponseWriter: c.Writer, limit: limit}
+	c.Writer = r.writer
+	return r
+}
+
+func (r *bodyRecorder) append(c *gin.Context, dict *zerolog.Event) {
+	if r.optIn && !c.GetBool(BodyCaptureKey) {
+		return
+	}
+	ctx := c.Request.Context()
+	if r.req != nil && r.allowed(c.ContentType()) {
+		// ハンドラーが読まなかったボディを読む
+		_, _ = io.Copy(io.Discard, io.LimitReader(r.req, int64(r.req.limit+1-r.req.buf.Len())))
+		r.appendBody(ctx, dict, "requestBody", c.ContentType(), r.req.buf.Bytes(), c.Request.ContentLength)
+	}
+	ct := r.writer.Header().Get("Content-Type")
+	if r.allowed(ct) {
+		r.appendBody(ctx, dict, "responseBody", ct, r.writer.buf.Bytes(), int64(r.writer.Size()))
+	}
+}
+
+func (r *bodyRecorder) allowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range r.contentTypes {
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendBody はボディを追加します。sizeはボディ全体のサイズで、不明な場合は負の値です。
+func (r *bodyRecorder) appendBody(ctx context.Context, dict *zerolog.Event, key, contentType string, data []byte, size int64) {
+	if len(data) == 0 {
+		return
+	}
+	var more int64
+	if limit := r.redactor.MaxBodySize(); len(data) > limit {
+		data, more = data[:limit], -1
+		if size > int64(limit) {
+			more = size - int64(limit)
+		}
+	}
+	body := r.redactor.Body(ctx, contentType, data, more)
+	if data, ok := masking.CompactJSON(body); ok {
+		dict.RawJSON(key, data)
+	} else {
+		dict.Str(key, body)
+	}
+}
+
+type bodyReader struct {
+	io.ReadCloser
+	limit int
+	buf   bytes.Buffer
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if rest := r.limit + 1 - r.buf.Len(); n > 0 && rest > 0 {
+		r.buf.Write(p[:min(n, rest)])
+	}
+	return n, err
+}
+
+type bodyWriter struct {
+	gin.ResponseWriter
+	limit int
+	buf   bytes.Buffer
+}
+
+func (w *bodyWriter) Write(p []byte) (int, error) {
+	w.record(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) record(p []byte) {
+	if rest := w.limit + 1 - w.buf.Len(); rest > 0 {
+		w.buf.Write(p[:min(len(p), rest)])
+	}
+}
@@ -11,8 +11,58 @@ import (
 	"github.com/rs/zerolog"
 )
 
+var logger = log.Named("ginlog")
+
+// SetLogger はginlogが出力に使うロガーを変更します。
+// デフォルトは log.Named("ginlog") です。
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
 func AccessLog(f ...func(c *gin.Context, e *zerolog.Event) *zerolog.Event) gin.HandlerFunc {
+	return AccessLogWith(WithFilter(f...))
+}
+
+type AccessLogOption func(o *accessLogOption)
+
+// WithFilter はアクセスログのイベントに適用するフィルターを追加します。
+// フィルターがnilを返すとアクセスログは出力されません。
+func WithFilter(f ...func(c *gin.Context, e *zerolog.Event) *zerolog.Event) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.filters = append(o.filters, f...)
+	}
+}
+
+// WithBodyCapture はリクエストとレスポンスのボディを httpRequest.requestBody と httpRequest.responseBody に記録します。
+func WithBodyCapture(opts ...BodyOption) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.body = newBodyCapture(opts...)
+	}
+}
+
+type accessLogOption struct {
+	filters []func(c *gin.Context, e *zerolog.Event) *zerolog.Event
+	body    *bodyCapture
+	levels  levelMapping
+	skip    skipRules
+}
+
+// AccessLogWith はオプションを指定してアクセスログを出力するハンドラーを返します。
+// 環境変数 AccessLogSkipEnv のスキップルールはオプションに追加されます。
+func AccessLogWith(opts ...AccessLogOption) gin.HandlerFunc {
+	o := &accessLogOption{}
+	for _, opt := range append(opts, envSkipRules()...) {
+		opt(o)
+	}
 	return func(c *gin.Context) {
+		if o.skip.skip(c) {
+			c.Next()
+			return
+		}
+		var body *bodyRecorder
+		if o.body != nil {
+			body = o.body.start(c)
+		}
 		// Start timer
 		start := time.Now()
 		// Process request
@@ -20,13 +70,31 @@ func AccessLog(f ...func(c *gin.Context, e *zerolog.Event) *zerolog.Event) gin.H
 		// Stop timer
 		end := time.Now()
 		latency := end.Sub(start)
-		JsonLog(c, func(c *gin.Context, e *zerolog.Event) {
+		if !o.skip.sampled(c) {
+			return
+		}
+		level, slow := o.levels.level(c, latency)
+		e := newEvent(c.Request.Context(), level)
+		if e == nil {
+			return
+		}
+		if slow {
+			e = e.Bool("slow", true)
+		}
+		jsonLog(c, e, func(c *gin.Context, e *zerolog.Event) {
 			e.Str("latency", fmt.Sprintf("%vs", latency.Seconds()))
-		}, f...)
+			if body != nil {
+				body.append(c, e)
+			}
+		}, o.filters...)
 	}
 }
 
 func JsonLog(c *gin.Context, f func(c *gin.Context, e *zerolog.Event), filters ...func(c *gin.Context, e *zerolog.Event) *zerolog.Event) {
+	jsonLog(c, logger.Info(c.Request.Context()), f, filters...)
+}
+
+func jsonLog(c *gin.Context, e *zerolog.Event, f func(c *gin.Context, e *zerolog.Event), filters ...func(c *gin.Context, e *zerolog.Event) *zerolog.Event) {
 	req := c.Request
 	ctx := req.Context()
 	ua := req.Header.Get(headers.UserAgent)
@@ -47,7 +115,7 @@ func JsonLog(c *gin.Context, f func(c *gin.Context, e *zerolog.Event), filters .
 	if f != nil {
 		f(c, dict)
 	}
-	e := log.EmbedObject(ctx, log.Info(ctx).Dict("httpRequest", dict))
+	e = log.EmbedObject(ctx, e.Dict("httpRequest", dict))
 	for _, filter := range filters {
 		if e = filter(c, e); e == nil {
 			return
@@ -4,17 +4,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
+	"github.com/goccha/logging/httplog"
 	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/masking"
 	"github.com/goccha/logging/tracing"
 	"github.com/goccha/logging/tracing/tracelog"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -111,3 +125,311 @@ func PerformRequest(r http.Handler, method, path string, headers ...header) *htt
 	r.ServeHTTP(w, req)
 	return w
 }
+
+func TestTraceRequest_ResponseRequestId(t *testing.T) {
+	tracelog.Setup(tracelog.WithNewFunc(nil), tracelog.WithRequestIdGenerator(func(ctx context.Context, req *http.Request) string {
+		return "generated-id"
+	}))
+	defer tracelog.Setup(tracelog.WithRequestIdGenerator(nil))
+	log.SetGlobalOut(&bytes.Buffer{})
+
+	router := gin.New()
+	router.Use(TraceRequest(WithResponseRequestId(true))).
+		GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+	w := PerformRequest(router, "GET", "/test")
+	assert.Equal(t, "generated-id", w.Header().Get("X-Request-ID"))
+	w = PerformRequest(router, "GET", "/test", header{Key: "X-Request-ID", Value: "client-id"})
+	assert.Equal(t, "client-id", w.Header().Get("X-Request-ID"))
+}
+
+func TestAccessLogWith_BodyCapture(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []BodyOption
+		route    []gin.HandlerFunc
+		body     string
+		wantReq  string
+		wantResp string
+	}{
+		{
+			name:     "masking",
+			opts:     []BodyOption{BodyMasking(masking.New("password", "token"))},
+			body:     `{"user":"test","password":"qwerty"}`,
+			wantReq:  `{"password":"*****","user":"test"}`,
+			wantResp: `{"token":"*****"}`,
+		},
+		{
+			name:     "truncate",
+			opts:     []BodyOption{BodyLimit(10)},
+			body:     `{"user":"test","password":"qwerty"}`,
+			wantReq:  `"{\"user\":\"t...(truncated 25 bytes)"`,
+			wantResp: `"{\"token\":\"...(truncated 8 bytes)"`,
+		},
+		{
+			name: "opt-in",
+			opts: []BodyOption{BodyOptIn()},
+			body: `{"user":"test"}`,
+		},
+		{
+			name:     "opt-in route",
+			opts:     []BodyOption{BodyOptIn()},
+			route:    []gin.HandlerFunc{CaptureBody()},
+			body:     `{"user":"test"}`,
+			wantReq:  `{"user":"test"}`,
+			wantResp: `{"token":"secret"}`,
+		},
+		{
+			name:     "content type",
+			opts:     []BodyOption{BodyContentTypes("text/*")},
+			body:     `{"user":"test"}`,
+			wantResp: ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			SetLogger(log.New(log.WithOut(buf)))
+			defer SetLogger(log.Named("ginlog"))
+
+			router := gin.New()
+			router.Use(AccessLogWith(WithBodyCapture(tt.opts...)))
+			handlers := append(tt.route, func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"token": "secret"})
+			})
+			router.POST("/test", handlers...)
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			var m struct {
+				HttpRequest struct {
+					RequestBody  json.RawMessage `json:"requestBody"`
+					ResponseBody json.RawMessage `json:"responseBody"`
+				} `json:"httpRequest"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.wantReq, string(m.HttpRequest.RequestBody))
+			assert.Equal(t, tt.wantResp, string(m.HttpRequest.ResponseBody))
+		})
+	}
+}
+
+func TestAccessLogWith_Levels(t *testing.T) {
+	opts := []AccessLogOption{
+		WithStatusLevels(),
+		WithStatusLevel(zerolog.InfoLevel, http.StatusNotFound),
+		WithSlowThreshold(50 * time.Millisecond),
+		WithPathLevel(zerolog.Disabled, "/healthz"),
+		WithPathLevel(zerolog.DebugLevel, "/ready"),
+	}
+	tests := []struct {
+		path     string
+		status   int
+		sleep    time.Duration
+		severity string
+		slow     bool
+	}{
+		{path: "/ok", status: http.StatusOK, severity: "INFO"},
+		{path: "/error", status: http.StatusInternalServerError, severity: "ERROR"},
+		{path: "/bad", status: http.StatusBadRequest, severity: "WARNING"},
+		{path: "/missing", status: http.StatusNotFound, severity: "INFO"},
+		{path: "/slow", status: http.StatusOK, sleep: 60 * time.Millisecond, severity: "WARNING", slow: true},
+		{path: "/healthz", status: http.StatusOK},
+		{path: "/ready", status: http.StatusServiceUnavailable, severity: "DEBUG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			buf, errBuf := &bytes.Buffer{}, &bytes.Buffer{}
+			SetLogger(log.New(log.WithOut(buf), log.WithErr(errBuf)))
+			defer SetLogger(log.Named("ginlog"))
+
+			router := gin.New()
+			router.Use(AccessLogWith(opts...))
+			router.GET(tt.path, func(c *gin.Context) {
+				time.Sleep(tt.sleep)
+				c.Status(tt.status)
+			})
+			PerformRequest(router, http.MethodGet, tt.path)
+			assert.Equal(t, 0, errBuf.Len())
+			if tt.severity == "" {
+				assert.Equal(t, 0, buf.Len())
+				return
+			}
+			var m struct {
+				Severity string `json:"severity"`
+				Slow     bool   `json:"slow"`
+				Caller   string `json:"caller"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.severity, m.Severity)
+			assert.Equal(t, tt.slow, m.Slow)
+			assert.Equal(t, "", m.Caller)
+		})
+	}
+}
+
+func TestAccessLogWith_Skip(t *testing.T) {
+	t.Setenv(AccessLogSkipEnv, "ua:^kube-probe/;sample:/metrics=0")
+	opts := []AccessLogOption{
+		WithSkipPaths("/healthz"),
+		WithSkipPathPrefixes("/debug/"),
+		WithSkipGlobs("/static/*.css"),
+		WithSkipRegexps(regexp.MustCompile(`^/v[0-9]+/ping$`)),
+		WithSkipMethods(http.MethodOptions),
+		WithSuccessSampling(1, "/users/:id"),
+	}
+	tests := []struct {
+		method string
+		path   string
+		ua     string
+		status int
+		logged bool
+	}{
+		{method: http.MethodGet, path: "/healthz", status: http.StatusOK},
+		{method: http.MethodGet, path: "/debug/vars", status: http.StatusOK},
+		{method: http.MethodGet, path: "/static/app.css", status: http.StatusOK},
+		{method: http.MethodGet, path: "/static/app.js", status: http.StatusOK, logged: true},
+		{method: http.MethodGet, path: "/v1/ping", status: http.StatusOK},
+		{method: http.MethodOptions, path: "/users/1", status: http.StatusOK},
+		{method: http.MethodGet, path: "/users/1", ua: "kube-probe/1.30", status: http.StatusOK},
+		{method: http.MethodGet, path: "/users/1", status: http.StatusOK, logged: true},
+		{method: http.MethodGet, path: "/metrics", status: http.StatusOK},
+		{method: http.MethodGet, path: "/metrics", status: http.StatusInternalServerError, logged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			buf, errBuf := &bytes.Buffer{}, &bytes.Buffer{}
+			SetLogger(log.New(log.WithOut(buf), log.WithErr(errBuf)))
+			defer SetLogger(log.Named("ginlog"))
+
+			router := gin.New()
+			router.Use(AccessLogWith(opts...))
+			router.Handle(tt.method, tt.path, func(c *gin.Context) {
+				c.Status(tt.status)
+			})
+			PerformRequest(router, tt.method, tt.path, header{Key: "User-Agent", Value: tt.ua})
+			assert.Equal(t, tt.logged, buf.Len() > 0)
+		})
+	}
+	if _, err := ParseSkipRules("sample:/metrics=2"); err == nil {
+		t.Error("rate must be between 0 and 1")
+	}
+}
+
+func TestTraceRequest_Dump(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf), log.WithLevel(zerolog.DebugLevel)))
+	defer SetLogger(log.Named("ginlog"))
+
+	router := gin.New()
+	router.Use(TraceRequest(WithDump(true, httplog.DumpExcludeHeaders("X-Ignored"), httplog.DumpReveal(3), httplog.DumpResponseHeaders()))).
+		GET("/test", func(c *gin.Context) {
+			c.Header("Set-Cookie", "session=secret; Path=/; HttpOnly")
+			c.Status(http.StatusOK)
+		})
+	PerformRequest(router, http.MethodGet, "/test",
+		header{Key: "Authorization", Value: "Bearer abcdefgh"},
+		header{Key: "Cookie", Value: "a=1234; b=5678"},
+		header{Key: "Accept", Value: "text/html"},
+		header{Key: "Accept", Value: "application/json"},
+		header{Key: "X-Ignored", Value: "ignored"})
+
+	dumps := make(map[string]map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m struct {
+			Message string              `json:"message"`
+			Headers map[string][]string `json:"headers"`
+		}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Headers != nil {
+			dumps[m.Message] = m.Headers
+		}
+	}
+	req := dumps["dumpHeaders"]
+	assert.Equal(t, []string{"Bearer abc" + masking.MaskValue}, req["Authorization"])
+	assert.Equal(t, []string{"a=123" + masking.MaskValue + "; b=567" + masking.MaskValue}, req["Cookie"])
+	assert.Equal(t, []string{"text/html", "application/json"}, req["Accept"])
+	_, ok := req["X-Ignored"]
+	assert.Equal(t, false, ok)
+	assert.Equal(t, []string{"session=sec" + masking.MaskValue}, dumps["dumpResponseHeaders"]["Set-Cookie"])
+}
+
+func TestTraceRequest_ServerSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	log.SetGlobalOut(&bytes.Buffer{})
+
+	router := gin.New()
+	router.Use(TraceRequest(WithServerSpan(true), WithTracerProvider(tp))).
+		GET("/users/:id", func(c *gin.Context) {
+			_ = c.Error(errors.New("failed"))
+			c.Status(http.StatusInternalServerError)
+		})
+	PerformRequest(router, http.MethodGet, "/users/1?q=1",
+		header{Key: "Traceparent", Value: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d", len(spans))
+	}
+	span := spans[0]
+	assert.Equal(t, "GET /users/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.SpanContext().TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", span.Parent().SpanID().String())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "/users/:id", attrs[semconv.HTTPRouteKey].AsString())
+	assert.Equal(t, "/users/1", attrs[semconv.URLPathKey].AsString())
+	assert.Equal(t, "q=1", attrs[semconv.URLQueryKey].AsString())
+	assert.Equal(t, int64(http.StatusInternalServerError), attrs[semconv.HTTPResponseStatusCodeKey].AsInt64())
+	assert.Equal(t, 1, len(span.Events()))
+	assert.Equal(t, semconv.ExceptionEventName, span.Events()[0].Name)
+}
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	router := gin.New()
+	router.Use(Metrics(WithMeterProvider(mp))).
+		GET("/users/:id", func(c *gin.Context) {
+			c.String(http.StatusNotFound, "not found")
+		})
+	PerformRequest(router, http.MethodGet, "/users/1")
+	PerformRequest(router, http.MethodGet, "/users/2")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+	duration := metrics["http.server.request.duration"].(metricdata.Histogram[float64])
+	assert.Equal(t, 1, len(duration.DataPoints))
+	point := duration.DataPoints[0]
+	assert.Equal(t, uint64(2), point.Count)
+	route, _ := point.Attributes.Value(semconv.HTTPRouteKey)
+	assert.Equal(t, "/users/:id", route.AsString())
+	class, _ := point.Attributes.Value(tracing.StatusClassKey)
+	assert.Equal(t, "4xx", class.AsString())
+	size := metrics["http.server.response.body.size"].(metricdata.Histogram[int64])
+	assert.Equal(t, int64(18), size.DataPoints[0].Sum)
+	active := metrics["http.server.active_requests"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(0), active.DataPoints[0].Value)
+}
@@ -0,0 +1,87 @@
+package ginlog
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// WithStatusLevels はステータスコードに応じてアクセスログのレベルを変更します。
+// 5xxはERROR、4xxはWARNING、それ以外はINFOで出力されます。
+func WithStatusLevels() AccessLogOption {
+	return func(o *accessLogOption) {
+		o.levels.statusMapping = true
+	}
+}
+
+// WithStatusLevel は指定したステータスコードのアクセスログのレベルを設定します。
+// WithStatusLevels のマッピングより優先されます。zerolog.Disabled を指定するとアクセスログは出力されません。
+func WithStatusLevel(level zerolog.Level, statuses ...int) AccessLogOption {
+	return func(o *accessLogOption) {
+		if o.levels.statuses == nil {
+			o.levels.statuses = make(map[int]zerolog.Level, len(statuses))
+		}
+		for _, status := range statuses {
+			o.levels.statuses[status] = level
+		}
+	}
+}
+
+// WithSlowThreshold は処理時間がthresholdを超えたリクエストを slow: true を付けてWARNING以上で出力します。
+func WithSlowThreshold(threshold time.Duration) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.levels.slow = threshold
+	}
+}
+
+// WithPathLevel は指定したパスのアクセスログのレベルを設定します。ヘルスチェックなどに使います。
+// 他の設定より優先されます。zerolog.Disabled を指定するとアクセスログは出力されません。
+func WithPathLevel(level zerolog.Level, paths ...string) AccessLogOption {
+	return func(o *accessLogOption) {
+		if o.levels.paths == nil {
+			o.levels.paths = make(map[string]zerolog.Level, len(paths))
+		}
+		for _, path := range paths {
+			o.levels.paths[path] = level
+		}
+	}
+}
+
+type levelMapping struct {
+	statusMapping bool
+	statuses      map[int]zerolog.Level
+	slow          time.Duration
+	paths         map[string]zerolog.Level
+}
+
+// level はアクセスログのレベルと処理時間がしきい値を超えたかどうかを返します。
+func (m *levelMapping) level(c *gin.Context, latency time.Duration) (zerolog.Level, bool) {
+	if level, ok := m.paths[c.Request.URL.Path]; ok {
+		return level, false
+	}
+	status := c.Writer.Status()
+	level, ok := m.statuses[status]
+	if !ok {
+		switch {
+		case m.statusMapping && status >= http.StatusInternalServerError:
+			level = zerolog.ErrorLevel
+		case m.statusMapping && status >= http.StatusBadRequest:
+			level = zerolog.WarnLevel
+		default:
+			level = zerolog.InfoLevel
+		}
+	}
+	slow := m.slow > 0 && latency > m.slow
+	if slow && level < zerolog.WarnLevel {
+		level = zerolog.WarnLevel
+	}
+	return level, slow
+}
+
+// newEvent はアクセスログのイベントを返します。レベルに関わらず同じ出力先に書き込まれ、重要度のみが変わります。
+func newEvent(ctx context.Context, level zerolog.Level) *zerolog.Event {
+	return logger.WithLevel(ctx, level)
+}
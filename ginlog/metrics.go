@@ -0,0 +1,45 @@
+package ginlog
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccha/logging/tracing"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type MetricsOption func(o *metricsOption)
+
+// WithMeterProvider はメトリクスを記録するプロバイダーを設定します。
+// デフォルトはグローバルのプロバイダーです。
+func WithMeterProvider(mp metric.MeterProvider) MetricsOption {
+	return func(o *metricsOption) {
+		o.provider = mp
+	}
+}
+
+type metricsOption struct {
+	provider metric.MeterProvider
+}
+
+// Metrics はHTTPサーバーのメトリクスを記録します。
+// 計装は tracing.ServerMetrics を参照してください。ルートは c.FullPath() で、一致しない場合は http.route なしで記録されます。
+func Metrics(opts ...MetricsOption) gin.HandlerFunc {
+	o := &metricsOption{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	m, err := tracing.NewServerMetrics(o.provider, ScopeName)
+	if err != nil {
+		logger.Error(context.Background()).Err(err).Msg("failed to create metrics")
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	return func(c *gin.Context) {
+		end := m.Begin(c.Request.Context(), c.Request.Method)
+		c.Next()
+		// 書き込まれていない場合のサイズは-1です
+		end(c.FullPath(), c.Writer.Status(), c.Request.ContentLength, int64(max(c.Writer.Size(), 0)))
+	}
+}
@@ -0,0 +1,90 @@
+package ginlog
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccha/logging/httplog"
+	"github.com/goccha/logging/log"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RecoveryOption func(o *recoveryOption)
+
+// WithRecoveryHandler はパニックから回復したときのレスポンスを書き込む関数を設定します。
+// レスポンスが書き込み済みの場合は呼ばれません。デフォルトはステータス500で中断します。
+func WithRecoveryHandler(f func(c *gin.Context, err any)) RecoveryOption {
+	return func(o *recoveryOption) {
+		o.handler = f
+	}
+}
+
+// WithBrokenPipe はクライアントの切断によるパニックを判定する関数を設定します。
+// 切断と判定されたパニックはスタックトレースなしでNOTICEで出力され、レスポンスは書き込まれません。
+// nilの場合は IsBrokenPipe が使われます。
+func WithBrokenPipe(f func(err any) bool) RecoveryOption {
+	return func(o *recoveryOption) {
+		if f == nil {
+			f = IsBrokenPipe
+		}
+		o.brokenPipe = f
+	}
+}
+
+type recoveryOption struct {
+	handler    func(c *gin.Context, err any)
+	brokenPipe func(err any) bool
+}
+
+// Recovery はパニックから回復し、トレースコンテキストとスタックトレース付きでCRITICALのログを出力します。
+// http.ErrAbortHandler はサーバーがレスポンスを中断するように再度パニックします。
+func Recovery(opts ...RecoveryOption) gin.HandlerFunc {
+	o := &recoveryOption{
+		handler: func(c *gin.Context, err any) {
+			c.AbortWithStatus(http.StatusInternalServerError)
+		},
+		brokenPipe: IsBrokenPipe,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(c *gin.Context) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				err, ok := v.(error)
+				if !ok {
+					err = fmt.Errorf("%v", v)
+				}
+				ctx := c.Request.Context()
+				if o.brokenPipe(v) {
+					_ = c.Error(err)
+					c.Abort()
+					jsonLog(c, logger.Notice(ctx), nil)
+					return
+				}
+				span := trace.SpanFromContext(ctx)
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				if c.Writer.Written() {
+					c.Abort()
+				} else {
+					o.handler(c, v)
+				}
+				_ = c.Error(err)
+				e := log.Err(logger.Critical(ctx), err).Str("panic", fmt.Sprint(v))
+				jsonLog(c, e, nil)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// IsBrokenPipe はパニックがクライアントの切断によるものかどうかを返します。httplog.IsBrokenPipe と同じです。
+func IsBrokenPipe(v any) bool {
+	return httplog.IsBrokenPipe(v)
+}
@@ -0,0 +1,109 @@
+package ginlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/goccha/logging/log"
+)
+
+func TestRecovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		panic    any
+		code     int
+		severity string
+		stack    bool
+	}{
+		{name: "panic", panic: "boom", code: http.StatusInternalServerError, severity: "CRITICAL", stack: true},
+		{name: "broken pipe", panic: &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)},
+			code: http.StatusOK, severity: "NOTICE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			SetLogger(log.New(log.WithOut(buf), log.WithErr(buf)))
+			defer SetLogger(log.Named("ginlog"))
+
+			router := gin.New()
+			router.Use(Recovery())
+			router.GET("/test", func(c *gin.Context) {
+				panic(tt.panic)
+			})
+			w := PerformRequest(router, http.MethodGet, "/test")
+			assert.Equal(t, tt.code, w.Code)
+
+			var m struct {
+				Severity    string `json:"severity"`
+				Panic       string `json:"panic"`
+				Stack       string `json:"stack_trace"`
+				Message     string `json:"message"`
+				HttpRequest struct {
+					Status int `json:"status"`
+				} `json:"httpRequest"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatal(err, buf.String())
+			}
+			assert.Equal(t, tt.severity, m.Severity)
+			assert.Equal(t, tt.code, m.HttpRequest.Status)
+			assert.NotEqual(t, "", m.Message)
+			if tt.stack {
+				assert.Equal(t, "boom", m.Panic)
+				assert.Equal(t, true, strings.Contains(m.Stack, "ginlog.TestRecovery"))
+			} else {
+				assert.Equal(t, "", m.Stack)
+			}
+		})
+	}
+}
+
+func TestRecovery_Written(t *testing.T) {
+	SetLogger(log.New(log.WithOut(&bytes.Buffer{}), log.WithErr(&bytes.Buffer{})))
+	defer SetLogger(log.Named("ginlog"))
+
+	router := gin.New()
+	router.Use(Recovery(WithRecoveryHandler(func(c *gin.Context, err any) {
+		t.Error("handler is called after the response is written")
+	})))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusCreated, "created")
+		panic("boom")
+	})
+	w := PerformRequest(router, http.MethodGet, "/test")
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestRecovery_NilBrokenPipe(t *testing.T) {
+	SetLogger(log.New(log.WithOut(&bytes.Buffer{}), log.WithErr(&bytes.Buffer{})))
+	defer SetLogger(log.Named("ginlog"))
+
+	router := gin.New()
+	router.Use(Recovery(WithBrokenPipe(nil)))
+	router.GET("/test", func(c *gin.Context) {
+		panic("boom")
+	})
+	w := PerformRequest(router, http.MethodGet, "/test")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRecovery_AbortHandler(t *testing.T) {
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/test", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+	defer func() {
+		assert.Equal(t, http.ErrAbortHandler, recover())
+	}()
+	PerformRequest(router, http.MethodGet, "/test")
+	t.Error("http.ErrAbortHandler is not re-panicked")
+}
@@ -0,0 +1,213 @@
+package ginlog
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccha/envar"
+	"github.com/goccha/http-constants/pkg/headers"
+)
+
+// AccessLogSkipEnv はアクセスログのスキップルールを設定する環境変数です。
+// AccessLogWith の作成時に読み込まれ、書式は ParseSkipRules を参照してください。
+const AccessLogSkipEnv = "ACCESS_LOG_SKIP"
+
+// WithSkipPaths は指定したパスに完全一致するリクエストのアクセスログを出力しません。
+func WithSkipPaths(paths ...string) AccessLogOption {
+	return func(o *accessLogOption) {
+		if o.skip.paths == nil {
+			o.skip.paths = make(map[string]struct{}, len(paths))
+		}
+		for _, p := range paths {
+			o.skip.paths[p] = struct{}{}
+		}
+	}
+}
+
+// WithSkipPathPrefixes は指定したプレフィックスで始まるパスのアクセスログを出力しません。
+func WithSkipPathPrefixes(prefixes ...string) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.skip.prefixes = append(o.skip.prefixes, prefixes...)
+	}
+}
+
+// WithSkipGlobs は path.Match のパターンに一致するパスのアクセスログを出力しません。
+func WithSkipGlobs(patterns ...string) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.skip.globs = append(o.skip.globs, patterns...)
+	}
+}
+
+// WithSkipRegexps は正規表現に一致するパスのアクセスログを出力しません。
+func WithSkipRegexps(res ...*regexp.Regexp) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.skip.regexps = append(o.skip.regexps, res...)
+	}
+}
+
+// WithSkipMethods は指定したメソッドのリクエストのアクセスログを出力しません。
+func WithSkipMethods(methods ...string) AccessLogOption {
+	return func(o *accessLogOption) {
+		for _, m := range methods {
+			o.skip.methods = append(o.skip.methods, strings.ToUpper(m))
+		}
+	}
+}
+
+// WithSkipUserAgents はUser-Agentが正規表現に一致するリクエストのアクセスログを出力しません。
+// Kubernetesのプローブは kube-probe/.* で除外できます。
+func WithSkipUserAgents(res ...*regexp.Regexp) AccessLogOption {
+	return func(o *accessLogOption) {
+		o.skip.userAgents = append(o.skip.userAgents, res...)
+	}
+}
+
+// WithSuccessSampling は指定したルートで成功したリクエスト(ステータスコードが400未満)のアクセスログを
+// rateの割合(0から1)で出力します。エラーはすべて出力されます。
+// ルートは c.FullPath() で、一致しない場合はパスと比較されます。"*" はすべてのルートに適用されます。
+func WithSuccessSampling(rate float64, routes ...string) AccessLogOption {
+	return func(o *accessLogOption) {
+		if o.skip.samples == nil {
+			o.skip.samples = make(map[string]float64, len(routes))
+		}
+		for _, route := range routes {
+			o.skip.samples[route] = rate
+		}
+	}
+}
+
+// ParseSkipRules はスキップルールの文字列をオプションに変換します。
+// ルールは "種類:値" を ; で区切って指定します。
+//
+//	path:/healthz;prefix:/debug/;glob:/static/*;regexp:^/v[0-9]+/ping$;method:OPTIONS;ua:^kube-probe/;sample:/metrics=0.01
+func ParseSkipRules(rules string) ([]AccessLogOption, error) {
+	opts := make([]AccessLogOption, 0, 4)
+	for _, rule := range strings.Split(rules, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		kind, value, ok := strings.Cut(rule, ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid skip rule: %s", rule)
+		}
+		switch kind {
+		case "path":
+			opts = append(opts, WithSkipPaths(value))
+		case "prefix":
+			opts = append(opts, WithSkipPathPrefixes(value))
+		case "glob":
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid skip rule: %s: %w", rule, err)
+			}
+			opts = append(opts, WithSkipGlobs(value))
+		case "regexp", "ua":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid skip rule: %s: %w", rule, err)
+			}
+			if kind == "ua" {
+				opts = append(opts, WithSkipUserAgents(re))
+			} else {
+				opts = append(opts, WithSkipRegexps(re))
+			}
+		case "method":
+			opts = append(opts, WithSkipMethods(value))
+		case "sample":
+			route, v, ok := strings.Cut(value, "=")
+			rate, err := strconv.ParseFloat(v, 64)
+			if !ok || err != nil || rate < 0 || rate > 1 {
+				return nil, fmt.Errorf("invalid skip rule: %s", rule)
+			}
+			opts = append(opts, WithSuccessSampling(rate, route))
+		default:
+			return nil, fmt.Errorf("invalid skip rule: %s", rule)
+		}
+	}
+	return opts, nil
+}
+
+// envSkipRules は AccessLogSkipEnv のスキップルールを返します。不正な場合はエラーを出力して無視します。
+func envSkipRules() []AccessLogOption {
+	rules := envar.Get(AccessLogSkipEnv).String("")
+	if rules == "" {
+		return nil
+	}
+	opts, err := ParseSkipRules(rules)
+	if err != nil {
+		logger.Error(context.Background()).Err(err).Str("env", AccessLogSkipEnv).Msg("ignored access log skip rules")
+		return nil
+	}
+	return opts
+}
+
+type skipRules struct {
+	paths      map[string]struct{}
+	prefixes   []string
+	globs      []string
+	regexps    []*regexp.Regexp
+	methods    []string
+	userAgents []*regexp.Regexp
+	samples    map[string]float64
+}
+
+// skip はリクエストのアクセスログを出力しない場合にtrueを返します。
+func (r *skipRules) skip(c *gin.Context) bool {
+	req := c.Request
+	p := req.URL.Path
+	if _, ok := r.paths[p]; ok {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	for _, glob := range r.globs {
+		if ok, _ := path.Match(glob, p); ok {
+			return true
+		}
+	}
+	for _, re := range r.regexps {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	for _, m := range r.methods {
+		if req.Method == m {
+			return true
+		}
+	}
+	if len(r.userAgents) > 0 {
+		ua := req.Header.Get(headers.UserAgent)
+		for _, re := range r.userAgents {
+			if re.MatchString(ua) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sampled は処理後のリクエストのアクセスログを出力する場合にtrueを返します。
+func (r *skipRules) sampled(c *gin.Context) bool {
+	if len(r.samples) == 0 || c.Writer.Status() >= http.StatusBadRequest {
+		return true
+	}
+	rate, ok := r.samples[c.FullPath()]
+	if !ok {
+		if rate, ok = r.samples[c.Request.URL.Path]; !ok {
+			if rate, ok = r.samples["*"]; !ok {
+				return true
+			}
+		}
+	}
+	return rand.Float64() < rate
+}
@@ -1,37 +1,64 @@
 package ginlog
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
-	"github.com/goccha/http-constants/pkg/headers"
+	"github.com/goccha/logging/httplog"
 	"github.com/goccha/logging/log"
 	"github.com/goccha/logging/tracing"
 	"github.com/goccha/logging/tracing/tracelog"
-	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
-func dumpHeaders(c *gin.Context) {
-	logger := log.Debug(c.Request.Context())
-	for k, v := range c.Request.Header {
-		if len(v) > 0 {
-			logger.Str(k, v[0])
-		}
-	}
-	logger.Msg("dumpHeaders")
-}
-
 type Option func(o *option)
 
 // WithDump はリクエストヘッダーのダンプを有効または無効にします。
+// ヘッダーは headers の下にすべての値が出力され、masking.RedactHeaders の値は伏せられます。
 // デフォルトはfalseです。
-func WithDump(dump bool) Option {
+func WithDump(dump bool, opts ...httplog.DumpOption) Option {
 	return func(o *option) {
 		o.dump = dump
+		o.headers = httplog.NewHeaderDump(opts...)
+	}
+}
+
+// WithResponseRequestId は有効なリクエストIDをレスポンスヘッダーに書き込むかどうかを設定します。
+// ヘッダー名は tracelog.Config.RequestIdHeaderName() です。デフォルトはfalseです。
+func WithResponseRequestId(enable bool) Option {
+	return func(o *option) {
+		o.responseRequestId = enable
+	}
+}
+
+// WithServerSpan はTraceRequestでサーバースパンを開始するかどうかを設定します。
+// 親のコンテキストはグローバルのプロパゲーターでリクエストヘッダーから取り出されます。
+// デフォルトはfalseで、リクエストのコンテキストにあるスパンに属性を設定します。
+func WithServerSpan(enable bool) Option {
+	return func(o *option) {
+		o.serverSpan = enable
+	}
+}
+
+// WithTracerProvider はサーバースパンを作成するプロバイダーを設定します。
+// デフォルトはグローバルのプロバイダーです。
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *option) {
+		o.provider = tp
 	}
 }
 
 type option struct {
-	dump bool
+	dump              bool
+	headers           *httplog.HeaderDump
+	responseRequestId bool
+	serverSpan        bool
+	provider          trace.TracerProvider
 }
 
 func (o *option) apply(options ...Option) *option {
@@ -41,35 +68,60 @@ func (o *option) apply(options ...Option) *option {
 	return o
 }
 
+// ScopeName はサーバースパンの計装スコープです。
+const ScopeName = "github.com/goccha/logging/ginlog"
+
 func TraceRequest(options ...Option) gin.HandlerFunc {
+	o := new(option).apply(options...)
 	return func(c *gin.Context) {
-		o := new(option).apply(options...)
-		if o.dump {
-			dumpHeaders(c)
-		}
 		ctx := c.Request.Context()
-		span := trace.SpanFromContext(ctx)
-		span.SetAttributes(semconv.HTTPScheme(c.Request.URL.Scheme))
-		span.SetAttributes(semconv.HTTPMethod(c.Request.Method))
-		span.SetAttributes(semconv.HTTPTarget(c.Request.URL.Path))
-		span.SetAttributes(semconv.HTTPURL(c.Request.URL.String()))
-		if l := c.Request.ContentLength; l > 0 {
-			span.SetAttributes(semconv.HTTPRequestContentLength(int(l)))
-		}
-		if ip := tracing.ClientIP(c.Request); ip != "" {
-			span.SetAttributes(semconv.HTTPClientIP(ip))
-		}
-		if ua := c.Request.Header.Get(headers.UserAgent); ua != "" {
-			span.SetAttributes(semconv.HTTPUserAgent(ua))
+		var span trace.Span
+		if o.serverSpan {
+			provider := o.provider
+			if provider == nil {
+				provider = otel.GetTracerProvider()
+			}
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(c.Request.Header))
+			ctx, span = provider.Tracer(ScopeName).Start(ctx, spanName(c), trace.WithSpanKind(trace.SpanKindServer))
+			defer span.End()
+		} else {
+			span = trace.SpanFromContext(ctx)
 		}
+		span.SetAttributes(tracing.RequestAttributes(c.Request, c.FullPath())...)
 		c.Request = c.Request.WithContext(tracelog.WithContext(ctx, c.Request))
+		if o.responseRequestId {
+			if id := tracelog.RequestId(c.Request.Context()); id != "" {
+				c.Header(tracelog.TraceConfig().RequestIdHeaderName(), id)
+			}
+		}
 		if o.dump {
-			log.Dump(ctx, log.Debug(ctx)).Msg("dump")
+			ctx := c.Request.Context()
+			o.headers.Log(logger.Debug(ctx), c.Request.Header, "dumpHeaders")
+			log.Dump(ctx, logger.Debug(ctx)).Msg("dump")
 		}
 		c.Next()
-		span.SetAttributes(semconv.HTTPStatusCode(c.Writer.Status()))
+		if o.dump && o.headers.Response() {
+			o.headers.Log(logger.Debug(c.Request.Context()), c.Writer.Header(), "dumpResponseHeaders")
+		}
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
 		if c.Writer.Size() > 0 {
-			span.SetAttributes(semconv.HTTPResponseContentLength(c.Writer.Size()))
+			span.SetAttributes(semconv.HTTPResponseBodySize(c.Writer.Size()))
+		}
+		for _, err := range c.Errors {
+			span.RecordError(err.Err)
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(status)))
+			span.SetStatus(codes.Error, c.Errors.ByType(gin.ErrorTypePrivate).String())
 		}
 	}
 }
+
+// spanName はサーバースパンの名前を "METHOD route" の形式で返します。
+func spanName(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return c.Request.Method + " " + route
+	}
+	return c.Request.Method
+}
@@ -0,0 +1,144 @@
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor writes a log of each outbound call as restylog.WriteLog does for HTTP requests.
+func UnaryClientInterceptor(options ...Option) grpc.UnaryClientInterceptor {
+	o := new(option).apply(options...)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		var responseSize int64
+		if err == nil {
+			responseSize = int64(size(reply))
+		}
+		writeClientLog(ctx, cc.Target(), method, err, time.Since(start), int64(size(req)), responseSize, o.filters, nil)
+		return err
+	}
+}
+
+// StreamClientInterceptor is the stream version of UnaryClientInterceptor.
+// The log is written when the stream ends, or when ctx is done for a stream the caller abandons.
+func StreamClientInterceptor(options ...Option) grpc.StreamClientInterceptor {
+	o := new(option).apply(options...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			writeClientLog(ctx, cc.Target(), method, err, time.Since(start), 0, 0, o.filters, nil)
+			return nil, err
+		}
+		s := &clientStream{ClientStream: cs, serverStreams: desc.ServerStreams}
+		s.finish = func(err error) {
+			writeClientLog(ctx, cc.Target(), method, err, time.Since(start), s.sendSize.Load(), s.recvSize.Load(), o.filters, func(d *zerolog.Event) {
+				d.Int64("requestCount", s.sendCount.Load()).Int64("responseCount", s.recvCount.Load())
+			})
+		}
+		// the stream context is also done when the stream ends, which is logged by RecvMsg or SendMsg
+		s.stop = context.AfterFunc(cs.Context(), func() {
+			if err := ctx.Err(); err != nil {
+				s.done(status.FromContextError(err).Err())
+			}
+		})
+		return s, nil
+	}
+}
+
+func writeClientLog(ctx context.Context, target, fullMethod string, err error, latency time.Duration, requestSize, responseSize int64,
+	filters []Filter, f func(d *zerolog.Event)) {
+	st := status.Convert(err)
+	service, method := splitMethod(fullMethod)
+	dict := zerolog.Dict().
+		Str("code", st.Code().String()).
+		Str("service", service).Str("method", method).
+		Int64("requestSize", requestSize).Int64("responseSize", responseSize).
+		Str("latency", fmt.Sprintf("%vs", latency.Seconds()))
+	if f != nil {
+		f(dict)
+	}
+	var e *zerolog.Event
+	if transportError(err) {
+		e = logger.Notice(ctx)
+	} else {
+		e = logger.Info(ctx)
+	}
+	e = e.Str("client", target).Dict("grpcClient", dict)
+	for _, filter := range filters {
+		if e = filter(ctx, fullMethod, err, e); e == nil {
+			return
+		}
+	}
+	if err != nil {
+		e.Msg(st.Message())
+	} else {
+		e.Send()
+	}
+}
+
+// transportError reports whether err is a failure to reach the server rather than a status it returned.
+func transportError(err error) bool {
+	if err == nil {
+		return false
+	}
+	st, ok := status.FromError(err)
+	return !ok || st.Code() == codes.Unavailable
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	finish        func(err error)
+	stop          func() bool
+	once          sync.Once
+	serverStreams bool
+	recvSize      atomic.Int64
+	sendSize      atomic.Int64
+	recvCount     atomic.Int64
+	sendCount     atomic.Int64
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sendCount.Add(1)
+		s.sendSize.Add(int64(size(m)))
+	} else if err != io.EOF {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.recvCount.Add(1)
+		s.recvSize.Add(int64(size(m)))
+		if !s.serverStreams {
+			s.done(nil)
+		}
+	case err == io.EOF:
+		s.done(nil)
+	default:
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) done(err error) {
+	s.once.Do(func() {
+		s.stop()
+		s.finish(err)
+	})
+}
@@ -0,0 +1,88 @@
+package grpclog
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goccha/logging/log"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+var logger = log.Named("grpclog")
+
+// SetLogger changes the logger used by grpclog.
+// The default is log.Named("grpclog").
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+// Filter modifies the access log event of a call. Returning nil discards it.
+type Filter func(ctx context.Context, method string, err error, e *zerolog.Event) *zerolog.Event
+
+type Option func(o *option)
+
+// WithFilter adds filters applied to the access log event.
+func WithFilter(filters ...Filter) Option {
+	return func(o *option) {
+		o.filters = append(o.filters, filters...)
+	}
+}
+
+type option struct {
+	filters []Filter
+}
+
+func (o *option) apply(options ...Option) *option {
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+// NewRequest converts the incoming metadata and the peer of ctx to an *http.Request,
+// so that tracing.NewFunc and tracing.ClientIP can be used for gRPC calls.
+// Metadata keys are canonicalized as HTTP header names, so x-request-id is read as X-Request-Id.
+func NewRequest(ctx context.Context, fullMethod string) *http.Request {
+	req := &http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     make(http.Header),
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, v := range md {
+			if strings.HasPrefix(k, ":") {
+				if k == ":authority" && len(v) > 0 {
+					req.Host = v[0]
+				}
+				continue
+			}
+			req.Header[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+	return req.WithContext(ctx)
+}
+
+func splitMethod(fullMethod string) (service, method string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+func size(m any) int {
+	if p, ok := m.(proto.Message); ok {
+		return proto.Size(p)
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+package grpclog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccha/logging/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestInterceptors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("grpclog"))
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor()), grpc.StreamInterceptor(StreamServerInterceptor()))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-forwarded-for", "192.0.2.1")
+	client := healthpb.NewHealthClient(conn)
+	if _, err = client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("lines = %d: %s", len(lines), buf.String())
+	}
+	type entry struct {
+		Message     string `json:"message"`
+		Client      string `json:"client"`
+		GrpcRequest *struct {
+			Code         string `json:"code"`
+			RemoteIp     string `json:"remoteIp"`
+			Service      string `json:"service"`
+			Method       string `json:"method"`
+			ResponseSize int    `json:"responseSize"`
+		} `json:"grpcRequest"`
+		GrpcClient *struct {
+			Code   string `json:"code"`
+			Method string `json:"method"`
+		} `json:"grpcClient"`
+	}
+	var entries []entry
+	for _, line := range lines {
+		var e entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if r := entries[0].GrpcRequest; r == nil || r.Code != "OK" || r.RemoteIp != "192.0.2.1" ||
+		r.Service != "grpc.health.v1.Health" || r.Method != "Check" || r.ResponseSize == 0 {
+		t.Errorf("server log = %s", lines[0])
+	}
+	if c := entries[1].GrpcClient; c == nil || c.Code != "OK" || entries[1].Client != "passthrough:///bufnet" {
+		t.Errorf("client log = %s", lines[1])
+	}
+	if r := entries[2].GrpcRequest; r == nil || r.Code != "NotFound" || entries[2].Message == "" {
+		t.Errorf("server log = %s", lines[2])
+	}
+	if c := entries[3].GrpcClient; c == nil || c.Code != "NotFound" {
+		t.Errorf("client log = %s", lines[3])
+	}
+}
+
+func TestUnaryClientInterceptor_TransportError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("grpclog"))
+
+	lis := bufconn.Listen(1024)
+	_ = lis.Close()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err == nil {
+		t.Fatal("no error")
+	}
+	var e struct {
+		Severity   string `json:"severity"`
+		GrpcClient struct {
+			Code string `json:"code"`
+		} `json:"grpcClient"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if e.Severity != "NOTICE" || e.GrpcClient.Code != "Unavailable" {
+		t.Errorf("client log = %s", buf.String())
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStreamClientInterceptor_Canceled(t *testing.T) {
+	buf := &syncBuffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("grpclog"))
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	var e struct {
+		GrpcClient struct {
+			Code          string `json:"code"`
+			Method        string `json:"method"`
+			ResponseCount int    `json:"responseCount"`
+		} `json:"grpcClient"`
+	}
+	for i := 0; buf.String() == ""; i++ {
+		if i == 100 {
+			t.Fatal("no log of the canceled stream")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &e); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if e.GrpcClient.Code != "Canceled" || e.GrpcClient.Method != "Watch" || e.GrpcClient.ResponseCount != 1 {
+		t.Errorf("client log = %s", buf.String())
+	}
+}
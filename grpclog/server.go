@@ -0,0 +1,103 @@
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccha/http-constants/pkg/headers"
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing"
+	"github.com/goccha/logging/tracing/tracelog"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor stores the tracing context built from the incoming metadata in the context of the call
+// and writes an access log after the handler returns.
+func UnaryServerInterceptor(options ...Option) grpc.UnaryServerInterceptor {
+	o := new(option).apply(options...)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		r := NewRequest(ctx, info.FullMethod)
+		ctx = tracelog.WithContext(ctx, r)
+		res, err := handler(ctx, req)
+		writeServerLog(ctx, r, info.FullMethod, err, time.Since(start), size(req), size(res), o.filters, nil)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor is the stream version of UnaryServerInterceptor.
+// The sizes in the access log are the totals of the messages received and sent.
+func StreamServerInterceptor(options ...Option) grpc.StreamServerInterceptor {
+	o := new(option).apply(options...)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		r := NewRequest(ss.Context(), info.FullMethod)
+		s := &serverStream{ServerStream: ss, ctx: tracelog.WithContext(ss.Context(), r)}
+		err := handler(srv, s)
+		writeServerLog(s.ctx, r, info.FullMethod, err, time.Since(start), s.recvSize, s.sendSize, o.filters, func(d *zerolog.Event) {
+			d.Int("requestCount", s.recvCount).Int("responseCount", s.sendCount)
+		})
+		return err
+	}
+}
+
+func writeServerLog(ctx context.Context, r *http.Request, fullMethod string, err error, latency time.Duration, requestSize, responseSize int,
+	filters []Filter, f func(d *zerolog.Event)) {
+	st := status.Convert(err)
+	service, method := splitMethod(fullMethod)
+	dict := zerolog.Dict().
+		Str("code", st.Code().String()).Str("remoteIp", tracing.ClientIP(r)).
+		Str("userAgent", r.Header.Get(headers.UserAgent)).
+		Str("service", service).Str("method", method).
+		Int("requestSize", requestSize).Int("responseSize", responseSize).
+		Str("latency", fmt.Sprintf("%vs", latency.Seconds()))
+	if f != nil {
+		f(dict)
+	}
+	e := log.EmbedObject(ctx, logger.Info(ctx).Dict("grpcRequest", dict))
+	for _, filter := range filters {
+		if e = filter(ctx, fullMethod, err, e); e == nil {
+			return
+		}
+	}
+	if err != nil {
+		e.Msg(st.Message())
+	} else {
+		e.Send()
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	recvSize  int
+	sendSize  int
+	recvCount int
+	sendCount int
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recvCount++
+		s.recvSize += size(m)
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sendCount++
+		s.sendSize += size(m)
+	}
+	return err
+}
@@ -0,0 +1,242 @@
+package httpclientlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goccha/logging/masking"
+	"github.com/rs/zerolog"
+)
+
+type DumpOption func(d *dumper)
+
+// WithMasking masks the values of JSON and form bodies with p.
+func WithMasking(p *masking.Processor) DumpOption {
+	return func(d *dumper) {
+		d.redactor.SetProcessor(p)
+	}
+}
+
+// WithMaskKeys masks the values of the keys in JSON and form bodies.
+func WithMaskKeys(keys ...string) DumpOption {
+	return func(d *dumper) {
+		d.redactor.SetProcessor(masking.New(keys...))
+	}
+}
+
+// WithRedactHeaders adds headers to masking.RedactHeaders.
+func WithRedactHeaders(headers ...string) DumpOption {
+	return func(d *dumper) {
+		d.redactor.AddHeaders(headers...)
+	}
+}
+
+// WithMaxBodySize sets the size above which bodies are truncated. The default is 64KiB.
+func WithMaxBodySize(size int) DumpOption {
+	return func(d *dumper) {
+		if size > 0 {
+			d.redactor.SetMaxBodySize(size)
+		}
+	}
+}
+
+type dumper struct {
+	redactor *masking.Redactor
+}
+
+func newDumper(opts ...DumpOption) *dumper {
+	d := &dumper{
+		redactor: masking.NewRedactor(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// dump is the dump of a request, written when the response body is closed.
+type dump struct {
+	*dumper
+	ctx      context.Context
+	start    time.Time
+	req      *http.Request
+	reqBody  []byte
+	reqMore  int64
+	recorder *requestRecorder
+}
+
+// request keeps the head of the request body. A body that cannot be read again with GetBody
+// is recorded as the transport reads it, so that streaming bodies are sent without buffering.
+func (d *dumper) request(req *http.Request) *dump {
+	dp := &dump{dumper: d, ctx: req.Context(), start: time.Now(), req: req}
+	if req.Body == nil || req.Body == http.NoBody {
+		return dp
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			head, _ := io.ReadAll(io.LimitReader(body, int64(d.redactor.MaxBodySize())+1))
+			_ = body.Close()
+			dp.reqBody, dp.reqMore = d.truncate(head, req.ContentLength)
+			return dp
+		}
+	}
+	dp.recorder = &requestRecorder{ReadCloser: req.Body, limit: d.redactor.MaxBodySize()}
+	req.Body = dp.recorder
+	return dp
+}
+
+// truncate returns the head of a body of the size and the number of the remaining bytes,
+// which is negative if the size is unknown.
+func (d *dumper) truncate(head []byte, size int64) ([]byte, int64) {
+	limit := d.redactor.MaxBodySize()
+	if len(head) <= limit {
+		return head, 0
+	}
+	head = head[:limit]
+	if size < 0 {
+		return head, -1
+	}
+	return head, size - int64(len(head))
+}
+
+func (d *dump) fail(err error) {
+	e := logger.Debug(d.ctx)
+	e.Str("client", "http").Dict("request", d.requestDict()).
+		Str("latency", fmt.Sprintf("%vs", time.Since(d.start).Seconds())).Err(err).Send()
+}
+
+// response wraps the response body, so that the dump is written when it is closed
+// without buffering streaming responses.
+func (d *dump) response(res *http.Response) {
+	res.Body = &bodyRecorder{ReadCloser: res.Body, dump: d, res: res}
+}
+
+func (d *dump) write(res *http.Response, body []byte, more int64) {
+	resp := zerolog.Dict().Dict("headers", d.headers(res.Header))
+	d.appendBody(resp, res.Header.Get("Content-Type"), body, more)
+	logger.Debug(d.ctx).Str("client", "http").Dict("request", d.requestDict()).
+		Int("status", res.StatusCode).Dict("response", resp).
+		Str("latency", fmt.Sprintf("%vs", time.Since(d.start).Seconds())).Send()
+}
+
+func (d *dump) requestDict() *zerolog.Event {
+	req := zerolog.Dict().Str("method", d.req.Method).Str("url", d.req.URL.String()).
+		Dict("headers", d.headers(d.req.Header))
+	body, more := d.reqBody, d.reqMore
+	if d.recorder != nil {
+		body, more = d.recorder.head(d.req.ContentLength)
+	}
+	d.appendBody(req, d.req.Header.Get("Content-Type"), body, more)
+	return req
+}
+
+func (d *dumper) headers(header http.Header) *zerolog.Event {
+	headers := zerolog.Dict()
+	for k, v := range header {
+		headers.Strs(k, d.redactor.Header(k, v))
+	}
+	return headers
+}
+
+// appendBody adds the body followed by a marker if more bytes were truncated.
+func (d *dump) appendBody(dict *zerolog.Event, contentType string, data []byte, more int64) {
+	body := d.redactor.Body(d.ctx, contentType, data, more)
+	if data, ok := masking.CompactJSON(body); ok {
+		dict.RawJSON("body", data)
+	} else {
+		dict.Str("body", body)
+	}
+}
+
+// requestRecorder keeps the head of the request body as it is read by the transport,
+// which may still be sending it while the response is read.
+type requestRecorder struct {
+	io.ReadCloser
+	limit int
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	size  int64
+	eof   bool
+}
+
+func (r *requestRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n > 0 {
+		r.size += int64(n)
+		if rest := r.limit - r.buf.Len(); rest > 0 {
+			r.buf.Write(p[:min(n, rest)])
+		}
+	}
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// head returns the head of the body read so far and the number of the remaining bytes,
+// which is negative if the size is unknown.
+func (r *requestRecorder) head(size int64) ([]byte, int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	head := bytes.Clone(r.buf.Bytes())
+	switch {
+	case r.eof:
+		return head, r.size - int64(len(head))
+	case size >= 0:
+		return head, size - int64(len(head))
+	}
+	return head, -1
+}
+
+// bodyRecorder keeps the head of the response body as it is read by the caller.
+type bodyRecorder struct {
+	io.ReadCloser
+	dump *dump
+	res  *http.Response
+	buf  bytes.Buffer
+	size int64
+	once sync.Once
+}
+
+func (b *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.size += int64(n)
+		if rest := b.dump.redactor.MaxBodySize() - b.buf.Len(); rest > 0 {
+			b.buf.Write(p[:min(n, rest)])
+		}
+	}
+	if err == io.EOF {
+		b.flush(true)
+	}
+	return n, err
+}
+
+func (b *bodyRecorder) Close() error {
+	b.flush(false)
+	return b.ReadCloser.Close()
+}
+
+func (b *bodyRecorder) flush(eof bool) {
+	b.once.Do(func() {
+		head := b.buf.Bytes()
+		var more int64
+		switch {
+		case eof:
+			more = b.size - int64(len(head))
+		case b.res.ContentLength >= 0:
+			// closed before the end
+			more = b.res.ContentLength - int64(len(head))
+		default:
+			more = -1
+		}
+		b.dump.write(b.res, head, more)
+	})
+}
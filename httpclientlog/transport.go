@@ -0,0 +1,131 @@
+package httpclientlog
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccha/http-constants/pkg/headers"
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing/tracelog"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var logger = log.Named("httpclient")
+
+// SetLogger changes the logger used by httpclientlog.
+// The default is log.Named("httpclient").
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+type Option func(t *Transport)
+
+// WithName sets the client name written to the log. The default is the host of the request.
+func WithName(name string) Option {
+	return func(t *Transport) {
+		t.name = name
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into the request headers.
+// The default is the global propagator.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(t *Transport) {
+		t.propagator = p
+	}
+}
+
+// WithDump enables the debug dumps of requests and responses with the options.
+func WithDump(opts ...DumpOption) Option {
+	return func(t *Transport) {
+		t.dump = newDumper(opts...)
+	}
+}
+
+// Transport is an http.RoundTripper that logs the requests sent through the base transport
+// in the same format as restylog.WriteLog.
+// It propagates the request ID and the trace context of the request context to the server.
+type Transport struct {
+	base       http.RoundTripper
+	name       string
+	propagator propagation.TextMapPropagator
+	dump       *dumper
+}
+
+// New wraps base. If base is nil, http.DefaultTransport is used.
+func New(base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{base: base}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	req = req.Clone(ctx)
+	name := tracelog.TraceConfig().RequestIdHeaderName()
+	if req.Header.Get(name) == "" {
+		if id := tracelog.RequestId(ctx); id != "" {
+			req.Header.Set(name, id)
+		}
+	}
+	propagator := t.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	var d *dump
+	if t.dump != nil && logger.Enabled(zerolog.DebugLevel) {
+		d = t.dump.request(req)
+	}
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+	t.writeLog(req, res, err, latency)
+	if d != nil {
+		if err != nil {
+			d.fail(err)
+		} else {
+			d.response(res)
+		}
+	}
+	return res, err
+}
+
+// CloseIdleConnections closes the idle connections of the base transport if it supports it,
+// so that http.Client.CloseIdleConnections keeps working.
+func (t *Transport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+func (t *Transport) writeLog(req *http.Request, res *http.Response, err error, latency time.Duration) {
+	ctx := req.Context()
+	var ev *zerolog.Event
+	var status int
+	if res != nil {
+		status = res.StatusCode
+		ev = logger.Info(ctx)
+	} else {
+		ev = logger.Notice(ctx)
+	}
+	name := t.name
+	if name == "" {
+		name = req.URL.Host
+	}
+	ev.Str("client", name).Dict("httpClient", zerolog.Dict().
+		Int("status", status).Str("userAgent", req.Header.Get(headers.UserAgent)).
+		Str("requestMethod", req.Method).Str("protocol", req.URL.Scheme).
+		Str("requestHost", req.URL.Host).Str("requestPath", req.URL.Path).
+		Str("latency", fmt.Sprintf("%vs", latency.Seconds()))).
+		Err(err).Send()
+}
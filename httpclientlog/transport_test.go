@@ -0,0 +1,174 @@
+package httpclientlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccha/logging/log"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type entry struct {
+	Severity   string `json:"severity"`
+	Client     string `json:"client"`
+	Error      string `json:"error"`
+	HttpClient *struct {
+		Status        int    `json:"status"`
+		RequestMethod string `json:"requestMethod"`
+		RequestHost   string `json:"requestHost"`
+		RequestPath   string `json:"requestPath"`
+	} `json:"httpClient"`
+	Request *struct {
+		Headers map[string][]string `json:"headers"`
+		Body    json.RawMessage     `json:"body"`
+	} `json:"request"`
+	Response *struct {
+		Body json.RawMessage `json:"body"`
+	} `json:"response"`
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []entry {
+	var list []entry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err, line)
+		}
+		list = append(list, e)
+	}
+	return list
+}
+
+func TestTransport(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(`{"token":"secret",`))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(`"echo":` + string(body) + `}`))
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("httpclient"))
+
+	client := &http.Client{Transport: New(nil, WithName("test"), WithPropagator(propagation.TraceContext{}),
+		WithDump(WithMaskKeys("password", "token")))}
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/users", strings.NewReader(`{"password":"qwerty"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if string(body) != `{"token":"secret","echo":{"password":"qwerty"}}` {
+		t.Errorf("body = %s", body)
+	}
+	if !strings.HasPrefix(traceparent, "00-"+span.SpanContext().TraceID().String()) {
+		t.Errorf("traceparent = %s", traceparent)
+	}
+
+	list := entries(t, buf)
+	if len(list) != 2 {
+		t.Fatalf("log = %s", buf.String())
+	}
+	if c := list[0].HttpClient; c == nil || list[0].Client != "test" || list[0].Severity != "INFO" ||
+		c.Status != http.StatusOK || c.RequestMethod != http.MethodPost || c.RequestPath != "/users" {
+		t.Errorf("access log = %+v", list[0])
+	}
+	d := list[1]
+	if d.Request == nil || d.Response == nil || len(d.Request.Headers["Authorization"]) != 1 ||
+		d.Request.Headers["Authorization"][0] != "Bearer *****" ||
+		string(d.Request.Body) != `{"password":"*****"}` ||
+		string(d.Response.Body) != `{"echo":{"password":"*****"},"token":"*****"}` {
+		t.Errorf("dump = %s", buf.String())
+	}
+}
+
+func TestTransport_Error(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf), log.WithLevel(zerolog.InfoLevel)))
+	defer SetLogger(log.Named("httpclient"))
+
+	client := &http.Client{Transport: New(nil, WithDump())}
+	if _, err := client.Get("http://127.0.0.1:1/"); err == nil {
+		t.Fatal("no error")
+	}
+	list := entries(t, buf)
+	if len(list) != 1 || list[0].Severity != "NOTICE" || list[0].HttpClient.Status != 0 || list[0].Client != "127.0.0.1:1" ||
+		list[0].Error == "" {
+		t.Errorf("log = %s", buf.String())
+	}
+}
+
+func TestTransport_StreamingBody(t *testing.T) {
+	received := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		head := make([]byte, 5)
+		_, _ = io.ReadFull(r.Body, head)
+		close(received)
+		rest, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(append(head, rest...))
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("httpclient"))
+
+	pr, pw := io.Pipe()
+	go func() {
+		// the rest is written only after the server has received the head
+		_, _ = pw.Write([]byte("hello"))
+		<-received
+		_, _ = pw.Write([]byte(" world"))
+		_ = pw.Close()
+	}()
+	client := &http.Client{Transport: New(nil, WithDump())}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, pr)
+	req.Header.Set("Content-Type", "text/plain")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	list := entries(t, buf)
+	if len(list) != 2 || list[1].Request == nil || string(list[1].Request.Body) != `"hello world"` {
+		t.Errorf("log = %s", buf.String())
+	}
+}
+
+type idleTransport struct {
+	http.RoundTripper
+	closed bool
+}
+
+func (t *idleTransport) CloseIdleConnections() {
+	t.closed = true
+}
+
+func TestTransport_CloseIdleConnections(t *testing.T) {
+	base := &idleTransport{}
+	(&http.Client{Transport: New(base)}).CloseIdleConnections()
+	if !base.closed {
+		t.Error("CloseIdleConnections is not forwarded")
+	}
+}
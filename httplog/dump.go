@@ -0,0 +1,98 @@
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/goccha/logging/masking"
+	"github.com/rs/zerolog"
+)
+
+type DumpOption func(d *HeaderDump)
+
+// DumpHeaders limits the dumped headers to the given ones.
+func DumpHeaders(names ...string) DumpOption {
+	return func(d *HeaderDump) {
+		if d.allow == nil {
+			d.allow = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			d.allow[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// DumpExcludeHeaders excludes the given headers from the dumps.
+func DumpExcludeHeaders(names ...string) DumpOption {
+	return func(d *HeaderDump) {
+		for _, name := range names {
+			d.deny[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// DumpRedactHeaders adds headers to masking.RedactHeaders.
+func DumpRedactHeaders(names ...string) DumpOption {
+	return func(d *HeaderDump) {
+		d.redactor.AddHeaders(names...)
+	}
+}
+
+// DumpReveal shows the first n characters of the redacted values. The default is 0.
+// The authentication scheme of Authorization, such as Bearer, and the cookie names are always shown.
+func DumpReveal(n int) DumpOption {
+	return func(d *HeaderDump) {
+		d.redactor.Reveal(n)
+	}
+}
+
+// DumpResponseHeaders dumps the response headers after the request has been handled.
+func DumpResponseHeaders() DumpOption {
+	return func(d *HeaderDump) {
+		d.response = true
+	}
+}
+
+// HeaderDump writes all values of the headers under a headers dict, redacting masking.RedactHeaders.
+type HeaderDump struct {
+	allow    map[string]bool
+	deny     map[string]bool
+	redactor *masking.Redactor
+	response bool
+}
+
+func NewHeaderDump(opts ...DumpOption) *HeaderDump {
+	d := &HeaderDump{
+		deny:     make(map[string]bool),
+		redactor: masking.NewRedactor(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Response reports whether the response headers are dumped.
+func (d *HeaderDump) Response() bool {
+	return d.response
+}
+
+// Log writes header under the headers dict of e with msg.
+func (d *HeaderDump) Log(e *zerolog.Event, header http.Header, msg string) {
+	if !e.Enabled() {
+		return
+	}
+	e.Dict("headers", d.Headers(header)).Msg(msg)
+}
+
+// Headers returns the dict of the headers to dump.
+func (d *HeaderDump) Headers(header http.Header) *zerolog.Event {
+	dict := zerolog.Dict()
+	for k, v := range header {
+		name := http.CanonicalHeaderKey(k)
+		if d.deny[name] || (d.allow != nil && !d.allow[name]) {
+			continue
+		}
+		dict.Strs(k, d.redactor.Header(name, v))
+	}
+	return dict
+}
@@ -0,0 +1,164 @@
+package httplog
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccha/http-constants/pkg/headers"
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing"
+	"github.com/goccha/logging/tracing/tracelog"
+	"github.com/rs/zerolog"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var logger = log.Named("httplog")
+
+// SetLogger changes the logger used by httplog.
+// The default is log.Named("httplog").
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+// Filter modifies the access log event. Returning nil discards it.
+type Filter func(req *http.Request, w ResponseWriter, e *zerolog.Event) *zerolog.Event
+
+type Option func(o *option)
+
+// WithDump enables or disables dumping of the request headers.
+// All values of the headers are written under headers, and the values of masking.RedactHeaders are redacted.
+// The default is false.
+func WithDump(dump bool, opts ...DumpOption) Option {
+	return func(o *option) {
+		o.dump = dump
+		o.headers = NewHeaderDump(opts...)
+	}
+}
+
+// WithFilter adds filters applied to the access log event.
+func WithFilter(filters ...Filter) Option {
+	return func(o *option) {
+		o.filters = append(o.filters, filters...)
+	}
+}
+
+// WithResponseRequestId enables or disables writing the effective request ID to the response header
+// named by tracelog.Config.RequestIdHeaderName. The default is false.
+func WithResponseRequestId(enable bool) Option {
+	return func(o *option) {
+		o.responseRequestId = enable
+	}
+}
+
+type option struct {
+	dump              bool
+	headers           *HeaderDump
+	responseRequestId bool
+	filters           []Filter
+}
+
+func (o *option) apply(options ...Option) *option {
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+// Middleware traces and logs the requests handled by next,
+// as ginlog.TraceRequest and ginlog.AccessLog do.
+func Middleware(next http.Handler, options ...Option) http.Handler {
+	return TraceRequest(AccessLog(next, options...), options...)
+}
+
+// TraceRequest sets the request attributes to the span in the request context
+// and stores the tracing context of tracelog in it.
+// http.route is set from the pattern matched by http.ServeMux when the mux receives the request passed to next.
+func TraceRequest(next http.Handler, options ...Option) http.Handler {
+	o := new(option).apply(options...)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rw := NewResponseWriter(w)
+		ctx := req.Context()
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(tracing.RequestAttributes(req, "")...)
+		req = req.WithContext(tracelog.WithContext(ctx, req))
+		if o.responseRequestId {
+			if id := tracelog.RequestId(req.Context()); id != "" {
+				rw.Header().Set(tracelog.TraceConfig().RequestIdHeaderName(), id)
+			}
+		}
+		if o.dump {
+			ctx := req.Context()
+			o.headers.Log(logger.Debug(ctx), req.Header, "dumpHeaders")
+			log.Dump(ctx, logger.Debug(ctx)).Msg("dump")
+		}
+		next.ServeHTTP(rw, req)
+		if o.dump && o.headers.Response() {
+			o.headers.Log(logger.Debug(req.Context()), rw.Header(), "dumpResponseHeaders")
+		}
+		if r := route(req); r != "" {
+			span.SetAttributes(semconv.HTTPRoute(r))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rw.Status()))
+		if rw.Size() > 0 {
+			span.SetAttributes(semconv.HTTPResponseBodySize(rw.Size()))
+		}
+	})
+}
+
+// route returns the path of the pattern matched by http.ServeMux without the method and the host.
+func route(req *http.Request) string {
+	if i := strings.IndexByte(req.Pattern, '/'); i >= 0 {
+		return req.Pattern[i:]
+	}
+	return ""
+}
+
+// AccessLog writes an access log with the httpRequest field after next has handled the request.
+func AccessLog(next http.Handler, options ...Option) http.Handler {
+	o := new(option).apply(options...)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rw := NewResponseWriter(w)
+		start := time.Now()
+		next.ServeHTTP(rw, req)
+		latency := time.Since(start)
+		JsonLog(req, rw, func(req *http.Request, e *zerolog.Event) {
+			e.Str("latency", fmt.Sprintf("%vs", latency.Seconds()))
+		}, o.filters...)
+	})
+}
+
+func JsonLog(req *http.Request, w ResponseWriter, f func(req *http.Request, e *zerolog.Event), filters ...Filter) {
+	jsonLog(req, w, logger.Info(req.Context()), "", f, filters...)
+}
+
+func jsonLog(req *http.Request, w ResponseWriter, e *zerolog.Event, msg string, f func(req *http.Request, e *zerolog.Event), filters ...Filter) {
+	ctx := req.Context()
+	ua := req.Header.Get(headers.UserAgent)
+	requestUrl := req.URL.String()
+	if req.URL.Scheme == "" {
+		scheme := "http"
+		if req.TLS != nil {
+			scheme = "https"
+		}
+		requestUrl = fmt.Sprintf("%s://%s%s", scheme, req.Host, requestUrl)
+	}
+	dict := zerolog.Dict().
+		Int("status", w.Status()).Str("remoteIp", tracing.ClientIP(req)).
+		Str("userAgent", ua).
+		Str("requestMethod", req.Method).Str("requestUrl", requestUrl).
+		Str("protocol", req.Proto).Int64("requestSize", req.ContentLength).
+		Int("responseSize", w.Size())
+	if f != nil {
+		f(req, dict)
+	}
+	e = log.EmbedObject(ctx, e.Dict("httpRequest", dict))
+	for _, filter := range filters {
+		if e = filter(req, w, e); e == nil {
+			return
+		}
+	}
+	e.Msg(msg)
+}
@@ -0,0 +1,251 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/masking"
+	"github.com/goccha/logging/tracing"
+	"github.com/rs/zerolog"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("httplog"))
+
+	var flusher, hijacker bool
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flusher = w.(http.Flusher)
+		_, hijacker = w.(http.Hijacker)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}), WithFilter(func(req *http.Request, w ResponseWriter, e *zerolog.Event) *zerolog.Event {
+		return e.Str("path", req.URL.Path)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/items", nil)
+	req.Header.Set("User-Agent", "test")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var m struct {
+		Path        string `json:"path"`
+		HttpRequest struct {
+			Status        int    `json:"status"`
+			ResponseSize  int    `json:"responseSize"`
+			RequestMethod string `json:"requestMethod"`
+			RequestUrl    string `json:"requestUrl"`
+			UserAgent     string `json:"userAgent"`
+			Latency       string `json:"latency"`
+		} `json:"httpRequest"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if !flusher || hijacker {
+		t.Errorf("flusher = %v, hijacker = %v", flusher, hijacker)
+	}
+	r := m.HttpRequest
+	if r.Status != http.StatusCreated || r.ResponseSize != 5 || r.RequestMethod != http.MethodPost ||
+		r.RequestUrl != "http://example.com/items" || r.UserAgent != "test" || r.Latency == "" || m.Path != "/items" {
+		t.Errorf("log = %s", buf.String())
+	}
+}
+
+func TestTraceRequest_Dump(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf), log.WithLevel(zerolog.DebugLevel)))
+	defer SetLogger(log.Named("httplog"))
+
+	h := TraceRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret; Path=/")
+	}), WithDump(true, DumpResponseHeaders()))
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept", "text/html")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	dumps := make(map[string]map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m struct {
+			Message string              `json:"message"`
+			Headers map[string][]string `json:"headers"`
+		}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Headers != nil {
+			dumps[m.Message] = m.Headers
+		}
+	}
+	if v := dumps["dumpHeaders"]["Authorization"]; len(v) != 1 || v[0] != "Bearer "+masking.MaskValue {
+		t.Errorf("Authorization = %v", v)
+	}
+	if v := dumps["dumpHeaders"]["Accept"]; len(v) != 1 || v[0] != "text/html" {
+		t.Errorf("Accept = %v", v)
+	}
+	if v := dumps["dumpResponseHeaders"]["Set-Cookie"]; len(v) != 1 || v[0] != "session="+masking.MaskValue {
+		t.Errorf("Set-Cookie = %v", v)
+	}
+}
+
+func TestResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewResponseWriter(rec)
+	if NewResponseWriter(w) != w {
+		t.Error("ResponseWriter is wrapped twice")
+	}
+	if w.Status() != http.StatusOK || w.Written() {
+		t.Errorf("status = %d, written = %v", w.Status(), w.Written())
+	}
+	w.(http.Flusher).Flush()
+	if !rec.Flushed || !w.Written() {
+		t.Error("not flushed")
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		t.Error("Hijacker is implemented without the underlying writer")
+	}
+	if _, ok := NewResponseWriter(struct{ http.ResponseWriter }{rec}).(http.Flusher); ok {
+		t.Error("Flusher is implemented without the underlying writer")
+	}
+	if u, ok := w.(interface{ Unwrap() http.ResponseWriter }); !ok || u.Unwrap() != rec {
+		t.Error("Unwrap does not return the underlying writer")
+	}
+}
+
+func TestResponseWriter_HTTP1(t *testing.T) {
+	var hijacker, readerFrom bool
+	var size int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := NewResponseWriter(w)
+		_, hijacker = rw.(http.Hijacker)
+		var rf io.ReaderFrom
+		if rf, readerFrom = rw.(io.ReaderFrom); readerFrom {
+			_, _ = rf.ReadFrom(strings.NewReader("hello"))
+		}
+		size = rw.Size()
+	}))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if !hijacker || !readerFrom || size != 5 {
+		t.Errorf("hijacker = %v, readerFrom = %v, size = %d", hijacker, readerFrom, size)
+	}
+}
+
+func TestTraceRequest_Route(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	h := TraceRequest(mux)
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil).WithContext(ctx))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	for _, attr := range spans[0].Attributes() {
+		if attr.Key == semconv.HTTPRouteKey {
+			if attr.Value.AsString() != "/items/{id}" {
+				t.Errorf("http.route = %s", attr.Value.AsString())
+			}
+			return
+		}
+	}
+	t.Error("http.route is not set")
+}
+
+func TestRecovery(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf), log.WithErr(buf)))
+	defer SetLogger(log.Named("httplog"))
+
+	h := Middleware(Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("code = %d", w.Code)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var m struct {
+		Severity    string `json:"severity"`
+		Panic       string `json:"panic"`
+		Stack       string `json:"stack_trace"`
+		HttpRequest struct {
+			Status int `json:"status"`
+		} `json:"httpRequest"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Severity != "CRITICAL" || m.Panic != "boom" || m.HttpRequest.Status != http.StatusInternalServerError ||
+		!strings.Contains(m.Stack, "httplog.TestRecovery") {
+		t.Errorf("log = %s", lines[0])
+	}
+	if len(lines) != 2 || !strings.Contains(lines[1], `"status":500`) {
+		t.Errorf("access log = %s", buf.String())
+	}
+}
+
+func TestRecovery_NilBrokenPipe(t *testing.T) {
+	SetLogger(log.New(log.WithOut(&bytes.Buffer{}), log.WithErr(&bytes.Buffer{})))
+	defer SetLogger(log.Named("httplog"))
+
+	h := Recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), WithBrokenPipe(nil))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("code = %d", w.Code)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("item"))
+	})
+	h := Metrics(mux, WithMeterProvider(mp))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.server.request.duration" {
+			continue
+		}
+		point := m.Data.(metricdata.Histogram[float64]).DataPoints[0]
+		if route, _ := point.Attributes.Value(semconv.HTTPRouteKey); route.AsString() != "/items/{id}" {
+			t.Errorf("route = %s", route.AsString())
+		}
+		if class, _ := point.Attributes.Value(tracing.StatusClassKey); class.AsString() != "2xx" {
+			t.Errorf("status class = %s", class.AsString())
+		}
+		return
+	}
+	t.Error("http.server.request.duration is not recorded")
+}
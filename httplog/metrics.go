@@ -0,0 +1,59 @@
+package httplog
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/goccha/logging/tracing"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ScopeName is the instrumentation scope of the metrics.
+const ScopeName = "github.com/goccha/logging/httplog"
+
+type MetricsOption func(o *metricsOption)
+
+// WithMeterProvider sets the provider of the metrics. The default is the global provider.
+func WithMeterProvider(mp metric.MeterProvider) MetricsOption {
+	return func(o *metricsOption) {
+		o.provider = mp
+	}
+}
+
+// WithRoute sets the function returning the route template of the request after it has been handled.
+// The default returns the path of http.Request.Pattern set by http.ServeMux, without the method and the host.
+func WithRoute(f func(req *http.Request) string) MetricsOption {
+	return func(o *metricsOption) {
+		o.route = f
+	}
+}
+
+type metricsOption struct {
+	provider metric.MeterProvider
+	route    func(req *http.Request) string
+}
+
+// Metrics records the HTTP server metrics of the requests handled by next.
+// See tracing.ServerMetrics for the instruments.
+// The route is read from the request passed to next, so wrap http.ServeMux directly:
+//
+//	httplog.Middleware(httplog.Metrics(mux))
+func Metrics(next http.Handler, opts ...MetricsOption) http.Handler {
+	o := &metricsOption{
+		route: route,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	m, err := tracing.NewServerMetrics(o.provider, ScopeName)
+	if err != nil {
+		logger.Error(context.Background()).Err(err).Msg("failed to create metrics")
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rw := NewResponseWriter(w)
+		end := m.Begin(req.Context(), req.Method)
+		next.ServeHTTP(rw, req)
+		end(o.route(req), rw.Status(), req.ContentLength, int64(rw.Size()))
+	})
+}
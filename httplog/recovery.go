@@ -0,0 +1,110 @@
+package httplog
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/goccha/logging/log"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RecoveryOption func(o *recoveryOption)
+
+// WithRecoveryHandler sets the function writing the response after a panic.
+// It is not called if the response has already been written.
+// The default writes 500 Internal Server Error.
+func WithRecoveryHandler(f func(w http.ResponseWriter, req *http.Request, err any)) RecoveryOption {
+	return func(o *recoveryOption) {
+		o.handler = f
+	}
+}
+
+// WithBrokenPipe sets the function detecting panics caused by client disconnects.
+// They are logged at NOTICE without the stack trace and no response is written.
+// A nil function falls back to IsBrokenPipe.
+func WithBrokenPipe(f func(err any) bool) RecoveryOption {
+	return func(o *recoveryOption) {
+		if f == nil {
+			f = IsBrokenPipe
+		}
+		o.brokenPipe = f
+	}
+}
+
+type recoveryOption struct {
+	handler    func(w http.ResponseWriter, req *http.Request, err any)
+	brokenPipe func(err any) bool
+}
+
+// Recovery recovers from panics in next and logs them at CRITICAL with the stack trace and the httpRequest field.
+// http.ErrAbortHandler is re-panicked, so that the server aborts the response.
+// To log with the tracing context, wrap it with TraceRequest or Middleware:
+//
+//	httplog.Middleware(httplog.Recovery(mux))
+func Recovery(next http.Handler, opts ...RecoveryOption) http.Handler {
+	o := &recoveryOption{
+		handler: func(w http.ResponseWriter, req *http.Request, err any) {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		},
+		brokenPipe: IsBrokenPipe,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rw := NewResponseWriter(w)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			err, ok := v.(error)
+			if !ok {
+				err = fmt.Errorf("%v", v)
+			}
+			ctx := req.Context()
+			if o.brokenPipe(v) {
+				jsonLog(req, rw, logger.Notice(ctx), err.Error(), nil)
+				return
+			}
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			if !rw.Written() {
+				o.handler(rw, req, v)
+			}
+			e := log.Err(logger.Critical(ctx), err).Str("panic", fmt.Sprint(v))
+			jsonLog(req, rw, e, err.Error(), nil)
+		}()
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// IsBrokenPipe reports whether the panic is caused by a client disconnect.
+func IsBrokenPipe(v any) bool {
+	err, ok := v.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var ne *net.OpError
+	if errors.As(err, &ne) {
+		var se *os.SyscallError
+		if errors.As(ne, &se) {
+			msg := strings.ToLower(se.Error())
+			return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+		}
+	}
+	return false
+}
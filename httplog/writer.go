@@ -0,0 +1,123 @@
+package httplog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter is an http.ResponseWriter that records the status and the size of the response.
+// It implements http.Flusher, http.Hijacker, http.Pusher and io.ReaderFrom only when the wrapped writer does.
+// Other interfaces are reached with http.NewResponseController.
+type ResponseWriter interface {
+	http.ResponseWriter
+	// Status returns the status code of the response, or 200 if it has not been written.
+	Status() int
+	// Size returns the number of bytes written to the body.
+	Size() int
+	// Written reports whether the header has been written.
+	Written() bool
+}
+
+// NewResponseWriter wraps w. If w is already a ResponseWriter, it is returned as is.
+func NewResponseWriter(w http.ResponseWriter) ResponseWriter {
+	if rw, ok := w.(ResponseWriter); ok {
+		return rw
+	}
+	rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+	if _, ok := w.(http.Flusher); !ok {
+		return rw
+	}
+	_, hijacker := w.(http.Hijacker)
+	_, readerFrom := w.(io.ReaderFrom)
+	_, pusher := w.(http.Pusher)
+	switch {
+	case hijacker && readerFrom:
+		return &http1Writer{flushWriter{rw}}
+	case pusher:
+		return &http2Writer{flushWriter{rw}}
+	}
+	return &flushWriter{rw}
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	size    int
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.written {
+		w.status = code
+		// 1xx responses are not final, so the status may still change.
+		w.written = code >= http.StatusOK || code == http.StatusSwitchingProtocols
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.written = true
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.written
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flushWriter wraps a writer implementing http.Flusher.
+type flushWriter struct {
+	*responseWriter
+}
+
+func (w *flushWriter) Flush() {
+	w.written = true
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+// http1Writer wraps a writer implementing http.Hijacker and io.ReaderFrom as well, as net/http does for HTTP/1.x.
+type http1Writer struct {
+	flushWriter
+}
+
+func (w *http1Writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		w.written = true
+	}
+	return conn, rw, err
+}
+
+func (w *http1Writer) ReadFrom(r io.Reader) (int64, error) {
+	w.written = true
+	n, err := w.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+	w.size += int(n)
+	return n, err
+}
+
+// http2Writer wraps a writer implementing http.Pusher as well, as net/http does for HTTP/2.
+type http2Writer struct {
+	flushWriter
+}
+
+func (w *http2Writer) Push(target string, opts *http.PushOptions) error {
+	return w.ResponseWriter.(http.Pusher).Push(target, opts)
+}
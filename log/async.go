@@ -0,0 +1,249 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// OverflowPolicy decides what AsyncWriter does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block waits until there is room in the buffer.
+	Block OverflowPolicy = iota
+	// DropNewest discards the line being written.
+	DropNewest
+	// DropOldest discards the oldest buffered line.
+	DropOldest
+	// DropBelowLevel discards the line being written if its level is below the threshold
+	// and waits otherwise. Lines without a level are kept.
+	DropBelowLevel
+)
+
+const (
+	defaultAsyncBufferSize     = 1024
+	defaultAsyncReportInterval = 10 * time.Second
+)
+
+type AsyncOption func(w *AsyncWriter)
+
+// AsyncBufferSize sets the number of lines the writer can hold.
+func AsyncBufferSize(size int) AsyncOption {
+	return func(w *AsyncWriter) {
+		if size > 0 {
+			w.size = size
+		}
+	}
+}
+
+// AsyncOverflow sets the policy applied when the buffer is full. The default is Block.
+func AsyncOverflow(policy OverflowPolicy) AsyncOption {
+	return func(w *AsyncWriter) {
+		w.policy = policy
+	}
+}
+
+// AsyncDropBelow sets the DropBelowLevel policy with the given threshold.
+func AsyncDropBelow(level zerolog.Level) AsyncOption {
+	return func(w *AsyncWriter) {
+		w.policy = DropBelowLevel
+		w.threshold = level
+	}
+}
+
+// AsyncReportInterval sets how often the number of dropped lines is logged.
+// A non-positive interval disables the report.
+func AsyncReportInterval(d time.Duration) AsyncOption {
+	return func(w *AsyncWriter) {
+		w.interval = d
+	}
+}
+
+type asyncEntry struct {
+	level zerolog.Level
+	data  []byte
+}
+
+// AsyncWriter is a zerolog.LevelWriter that writes to the underlying writer in a goroutine,
+// so that a slow writer does not block the caller.
+type AsyncWriter struct {
+	out       io.Writer
+	size      int
+	policy    OverflowPolicy
+	threshold zerolog.Level
+	interval  time.Duration
+
+	mu       sync.RWMutex
+	closed   bool
+	syncMu   sync.Mutex
+	pending  sync.WaitGroup
+	ch       chan asyncEntry
+	stop     chan struct{}
+	done     chan struct{}
+	dropped  atomic.Uint64
+	reported uint64
+}
+
+func NewAsyncWriter(w io.Writer, opts ...AsyncOption) *AsyncWriter {
+	aw := &AsyncWriter{
+		out:       w,
+		size:      defaultAsyncBufferSize,
+		threshold: zerolog.WarnLevel,
+		interval:  defaultAsyncReportInterval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(aw)
+	}
+	aw.ch = make(chan asyncEntry, aw.size)
+	go aw.run()
+	return aw
+}
+
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *AsyncWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	// the lock only guards closed, so that Close is never blocked by a full buffer
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return w.writeSync(p)
+	}
+	w.pending.Add(1)
+	w.mu.RUnlock()
+	data := make([]byte, len(p))
+	copy(data, p)
+	ok := w.enqueue(asyncEntry{level: level, data: data})
+	w.pending.Done()
+	if !ok {
+		return w.writeSync(p)
+	}
+	return len(p), nil
+}
+
+// enqueue buffers e according to the policy.
+// It returns false if the writer is closed while waiting for room in the buffer.
+func (w *AsyncWriter) enqueue(e asyncEntry) bool {
+	switch w.policy {
+	case DropNewest:
+		select {
+		case w.ch <- e:
+		default:
+			w.dropped.Add(1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case w.ch <- e:
+				return true
+			default:
+			}
+			select {
+			case <-w.ch:
+				w.dropped.Add(1)
+			default:
+			}
+		}
+	case DropBelowLevel:
+		if e.level < w.threshold {
+			select {
+			case w.ch <- e:
+			default:
+				w.dropped.Add(1)
+			}
+			return true
+		}
+		return w.send(e)
+	default:
+		return w.send(e)
+	}
+	return true
+}
+
+func (w *AsyncWriter) send(e asyncEntry) bool {
+	select {
+	case w.ch <- e:
+		return true
+	case <-w.stop:
+		return false
+	}
+}
+
+// writeSync writes p after the buffered lines have been flushed.
+func (w *AsyncWriter) writeSync(p []byte) (int, error) {
+	<-w.done
+	w.syncMu.Lock()
+	defer w.syncMu.Unlock()
+	return w.out.Write(p)
+}
+
+// Dropped returns the number of lines dropped so far.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case e := <-w.ch:
+			_, _ = w.out.Write(e.data)
+		case <-tick:
+			w.report()
+		case <-w.stop:
+			// no lines are buffered after the pending writes have returned
+			w.pending.Wait()
+			for {
+				select {
+				case e := <-w.ch:
+					_, _ = w.out.Write(e.data)
+				default:
+					w.report()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *AsyncWriter) report() {
+	total := w.dropped.Load()
+	if n := total - w.reported; n > 0 {
+		w.reported = total
+		logger := zerolog.New(w.out).With().Timestamp().Logger()
+		logger.Log().Str(zerolog.LevelFieldName, zerolog.WarnLevel.String()).Str("severity", "WARNING").Uint64("dropped", n).Uint64("total", total).
+			Msg("log lines dropped by async writer")
+	}
+}
+
+// Close flushes the buffered lines and stops the writer.
+// It returns ctx.Err() if ctx is done before all lines are written.
+// Lines written after Close are written synchronously.
+func (w *AsyncWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.stop)
+	}
+	w.mu.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,167 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type slowWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	delay chan struct{}
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.delay
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []AsyncOption
+		dropped uint64
+		lines   []string
+	}{
+		{name: "block", opts: []AsyncOption{AsyncOverflow(Block)}, lines: []string{"1", "2", "3", "4"}},
+		{name: "drop newest", opts: []AsyncOption{AsyncOverflow(DropNewest)}, dropped: 1, lines: []string{"1", "2", "3"}},
+		{name: "drop oldest", opts: []AsyncOption{AsyncOverflow(DropOldest)}, dropped: 1, lines: []string{"1", "3", "4"}},
+		{name: "drop below level", opts: []AsyncOption{AsyncDropBelow(zerolog.WarnLevel)}, lines: []string{"1", "2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &slowWriter{delay: make(chan struct{})}
+			opts := append([]AsyncOption{AsyncBufferSize(2), AsyncReportInterval(0)}, tt.opts...)
+			w := NewAsyncWriter(out, opts...)
+			_, _ = w.WriteLevel(zerolog.InfoLevel, []byte("1\n"))
+			// wait for the worker to take the first line
+			for len(w.ch) > 0 {
+				time.Sleep(time.Millisecond)
+			}
+			_, _ = w.WriteLevel(zerolog.InfoLevel, []byte("2\n"))
+			_, _ = w.WriteLevel(zerolog.InfoLevel, []byte("3\n"))
+			go func() {
+				for range 10 {
+					out.delay <- struct{}{}
+				}
+			}()
+			_, _ = w.WriteLevel(zerolog.ErrorLevel, []byte("4\n"))
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if err := w.Close(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if w.Dropped() != tt.dropped {
+				t.Errorf("Dropped() = %d, want %d", w.Dropped(), tt.dropped)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if tt.dropped > 0 {
+				if !strings.Contains(lines[len(lines)-1], `"dropped":1`) {
+					t.Errorf("report not found: %v", lines)
+				}
+				lines = lines[:len(lines)-1]
+			}
+			if strings.Join(lines, ",") != strings.Join(tt.lines, ",") {
+				t.Errorf("lines = %v, want %v", lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestAsyncWriter_DropBelowLevel(t *testing.T) {
+	out := &slowWriter{delay: make(chan struct{})}
+	w := NewAsyncWriter(out, AsyncBufferSize(2), AsyncReportInterval(0), AsyncDropBelow(zerolog.WarnLevel))
+	_, _ = w.WriteLevel(zerolog.InfoLevel, []byte("1\n"))
+	for len(w.ch) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	_, _ = w.WriteLevel(zerolog.InfoLevel, []byte("2\n"))
+	_, _ = w.WriteLevel(zerolog.DebugLevel, []byte("3\n"))
+	// the buffer is full
+	_, _ = w.WriteLevel(zerolog.InfoLevel, []byte("4\n"))
+	go func() {
+		for range 10 {
+			out.delay <- struct{}{}
+		}
+	}()
+	_, _ = w.Write([]byte("5\n"))
+	_, _ = w.WriteLevel(zerolog.ErrorLevel, []byte("6\n"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := w.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", w.Dropped())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if got := strings.Join(lines[:len(lines)-1], ","); got != "1,2,3,5,6" {
+		t.Errorf("lines = %v", lines)
+	}
+}
+
+func TestAsyncWriter_CloseSync(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewAsyncWriter(out, AsyncReportInterval(0))
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = w.Write([]byte("after close\n"))
+		}()
+	}
+	wg.Wait()
+	if n := strings.Count(out.String(), "after close\n"); n != 10 {
+		t.Errorf("written = %d, want 10", n)
+	}
+}
+
+func TestAsyncWriter_CloseDeadline(t *testing.T) {
+	out := &slowWriter{delay: make(chan struct{})}
+	w := NewAsyncWriter(out, AsyncBufferSize(1), AsyncReportInterval(0))
+	_, _ = w.Write([]byte("1\n"))
+	for len(w.ch) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	_, _ = w.Write([]byte("2\n"))
+	written := make(chan struct{})
+	go func() {
+		// blocks on the full buffer of the stalled writer
+		_, _ = w.Write([]byte("3\n"))
+		close(written)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := w.Close(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Close() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close() took %v", elapsed)
+	}
+	close(out.delay)
+	<-written
+	if got := strings.Split(strings.TrimSpace(out.String()), "\n"); strings.Join(got, ",") != "1,2,3" {
+		t.Errorf("lines = %v", got)
+	}
+}
@@ -3,17 +3,16 @@ package log
 import (
 	"io"
 	"os"
+	"time"
+
+	"github.com/goccha/envar"
 )
 
 func getWriter() (w io.Writer) {
 	w = os.Stdout
 	if v, ok := os.LookupEnv("LOGGING_OUT_FILE"); ok {
 		if v != "" {
-			if f, err := os.Create(v); err != nil {
-				panic(err)
-			} else {
-				w = f
-			}
+			w = openFile(v)
 		}
 	}
 	return
@@ -23,12 +22,56 @@ func getErrorWriter() (w io.Writer) {
 	w = os.Stderr
 	if v, ok := os.LookupEnv("LOGGING_ERROR_FILE"); ok {
 		if v != "" {
-			if f, err := os.Create(v); err != nil {
-				panic(err)
-			} else {
-				w = f
-			}
+			w = openFile(v)
 		}
 	}
 	return
 }
+
+func openFile(name string) io.Writer {
+	if opts := RotateOptionsFromEnv(); len(opts) > 0 {
+		if w, err := NewRotateWriter(name, opts...); err != nil {
+			panic(err)
+		} else {
+			return w
+		}
+	}
+	if f, err := os.OpenFile(name, defaultFileFlags, defaultFileMode); err != nil {
+		panic(err)
+	} else {
+		return f
+	}
+}
+
+// RotateOptionsFromEnv returns the rotation options configured by environment variables.
+//
+//	LOGGING_ROTATE_MAX_SIZE     maximum size in megabytes
+//	LOGGING_ROTATE_DAILY        rotate when the date changes
+//	LOGGING_ROTATE_MAX_BACKUPS  number of rotated files to keep
+//	LOGGING_ROTATE_MAX_AGE      maximum age of rotated files (e.g. 168h)
+//	LOGGING_ROTATE_COMPRESS     gzip rotated files
+//	LOGGING_REOPEN_ON_SIGHUP    reopen the file on SIGHUP
+func RotateOptionsFromEnv() []RotateOption {
+	opts := make([]RotateOption, 0, 6)
+	if v := envar.Get("LOGGING_ROTATE_MAX_SIZE").Int(0); v > 0 {
+		opts = append(opts, RotateMaxSize(v))
+	}
+	if envar.Get("LOGGING_ROTATE_DAILY").Bool(false) {
+		opts = append(opts, RotateDaily())
+	}
+	if v := envar.Get("LOGGING_ROTATE_MAX_BACKUPS").Int(0); v > 0 {
+		opts = append(opts, RotateMaxBackups(v))
+	}
+	if v := envar.Get("LOGGING_ROTATE_MAX_AGE").String(""); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			opts = append(opts, RotateMaxAge(d))
+		}
+	}
+	if envar.Get("LOGGING_ROTATE_COMPRESS").Bool(false) {
+		opts = append(opts, RotateCompress())
+	}
+	if envar.Get("LOGGING_REOPEN_ON_SIGHUP").Bool(false) {
+		opts = append(opts, ReopenOnSignal())
+	}
+	return opts
+}
@@ -0,0 +1,291 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type levelSetting struct {
+	level   zerolog.Level
+	base    *zerolog.Level
+	expires time.Time
+	timer   *time.Timer
+}
+
+func (s *levelSetting) stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+		s.expires = time.Time{}
+	}
+}
+
+type levelSnapshot struct {
+	global zerolog.Level
+	named  map[string]zerolog.Level
+}
+
+var levelState = struct {
+	sync.Mutex
+	global levelSetting
+	named  map[string]*levelSetting
+}{
+	named: make(map[string]*levelSetting),
+}
+
+var levels atomic.Pointer[levelSnapshot]
+
+func init() {
+	levels.Store(&levelSnapshot{global: zerolog.GlobalLevel()})
+}
+
+// ParseLevel converts a LOG_LEVEL value to a zerolog.Level.
+// Unknown values are reported as NoLevel and false.
+func ParseLevel(level string) (zerolog.Level, bool) {
+	switch level {
+	case "trace":
+		return zerolog.TraceLevel, true
+	case "debug":
+		return zerolog.DebugLevel, true
+	case "info":
+		return zerolog.InfoLevel, true
+	case "warn":
+		return zerolog.WarnLevel, true
+	case "error":
+		return zerolog.ErrorLevel, true
+	case "fatal":
+		return zerolog.FatalLevel, true
+	case "panic":
+		return zerolog.PanicLevel, true
+	case "disabled":
+		return zerolog.Disabled, true
+	default:
+		return zerolog.NoLevel, false
+	}
+}
+
+// ParseLevelOverrides parses a LOG_LEVEL_OVERRIDES value such as "resty=debug,ginlog=warn".
+func ParseLevelOverrides(overrides string) map[string]zerolog.Level {
+	m := make(map[string]zerolog.Level)
+	for _, v := range strings.Split(overrides, ",") {
+		name, level, ok := strings.Cut(strings.TrimSpace(v), "=")
+		if !ok || name == "" {
+			continue
+		}
+		if lvl, ok := ParseLevel(strings.TrimSpace(level)); ok {
+			m[strings.TrimSpace(name)] = lvl
+		}
+	}
+	return m
+}
+
+// Level returns the current global log level.
+func Level() zerolog.Level {
+	return levels.Load().global
+}
+
+// NamedLevel returns the level applied to the logger with the given name.
+// A name without an override inherits the override of its parent ("resty.debug" from "resty")
+// or the global level.
+func NamedLevel(name string) zerolog.Level {
+	return levels.Load().level(name)
+}
+
+func (s *levelSnapshot) level(name string) zerolog.Level {
+	for name != "" && len(s.named) > 0 {
+		if lvl, ok := s.named[name]; ok {
+			return lvl
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return s.global
+}
+
+// SetLevel changes the global log level.
+// If ttl is given, the level reverts to the previous one after ttl has elapsed.
+func SetLevel(level zerolog.Level, ttl ...time.Duration) {
+	levelState.Lock()
+	defer levelState.Unlock()
+	s := &levelState.global
+	temporary := s.timer != nil
+	s.stop()
+	if len(ttl) > 0 && ttl[0] > 0 {
+		if !temporary {
+			base := s.level
+			s.base = &base
+		}
+		s.expire(ttl[0], func() {
+			s.level = *s.base
+			s.base = nil
+		})
+	} else {
+		s.base = nil
+	}
+	s.level = level
+	publishLevels()
+}
+
+// SetNamedLevel overrides the level of the logger with the given name.
+// If ttl is given, the override reverts to the previous state after ttl has elapsed.
+func SetNamedLevel(name string, level zerolog.Level, ttl ...time.Duration) {
+	levelState.Lock()
+	defer levelState.Unlock()
+	s, ok := levelState.named[name]
+	temporary := ok && s.timer != nil
+	if ok {
+		s.stop()
+	} else {
+		s = &levelSetting{}
+		levelState.named[name] = s
+	}
+	if len(ttl) > 0 && ttl[0] > 0 {
+		if ok && !temporary {
+			base := s.level
+			s.base = &base
+		}
+		s.expire(ttl[0], func() {
+			if s.base != nil {
+				s.level = *s.base
+				s.base = nil
+			} else if levelState.named[name] == s {
+				delete(levelState.named, name)
+			}
+		})
+	} else {
+		s.base = nil
+	}
+	s.level = level
+	publishLevels()
+}
+
+// ResetNamedLevel removes the override of the logger with the given name.
+func ResetNamedLevel(name string) {
+	levelState.Lock()
+	defer levelState.Unlock()
+	if s, ok := levelState.named[name]; ok {
+		s.stop()
+		delete(levelState.named, name)
+		publishLevels()
+	}
+}
+
+func (s *levelSetting) expire(ttl time.Duration, revert func()) {
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		levelState.Lock()
+		defer levelState.Unlock()
+		if s.timer == timer {
+			s.timer = nil
+			s.expires = time.Time{}
+			revert()
+			publishLevels()
+		}
+	})
+	s.timer = timer
+	s.expires = time.Now().Add(ttl)
+}
+
+// publishLevels stores the current levels for the loggers.
+// zerolog's global level follows the global level only; the loggers of this package
+// filter by their own levels, so that named overrides do not affect other zerolog users.
+func publishLevels() {
+	snapshot := &levelSnapshot{global: levelState.global.level}
+	if len(levelState.named) > 0 {
+		snapshot.named = make(map[string]zerolog.Level, len(levelState.named))
+		for name, s := range levelState.named {
+			snapshot.named[name] = s.level
+		}
+	}
+	levels.Store(snapshot)
+	zerolog.SetGlobalLevel(snapshot.global)
+}
+
+type levelBody struct {
+	Logger    string            `json:"logger,omitempty"`
+	Level     string            `json:"level"`
+	TTL       string            `json:"ttl,omitempty"`
+	Expires   *time.Time        `json:"expires,omitempty"`
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+func currentLevel(name string) levelBody {
+	levelState.Lock()
+	defer levelState.Unlock()
+	snapshot := levels.Load()
+	body := levelBody{Logger: name, Level: snapshot.level(name).String()}
+	s := &levelState.global
+	if name != "" {
+		s = levelState.named[name]
+	} else if len(snapshot.named) > 0 {
+		body.Overrides = make(map[string]string, len(snapshot.named))
+		for k, v := range snapshot.named {
+			body.Overrides[k] = v.String()
+		}
+	}
+	if s != nil && !s.expires.IsZero() {
+		expires := s.expires
+		body.Expires = &expires
+	}
+	return body
+}
+
+// LevelHandler returns an http.Handler that reads (GET) and changes (PUT) the global log level.
+// The PUT body is {"level":"debug","ttl":"10m"}; ttl is optional.
+// With the "logger" query parameter the level of that named logger is read or overridden,
+// and DELETE removes the override.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("logger")
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			level, ok := ParseLevel(body.Level)
+			if !ok {
+				http.Error(w, "unknown level: "+body.Level, http.StatusBadRequest)
+				return
+			}
+			var ttl time.Duration
+			if body.TTL != "" {
+				var err error
+				if ttl, err = time.ParseDuration(body.TTL); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			if name != "" {
+				SetNamedLevel(name, level, ttl)
+			} else {
+				SetLevel(level, ttl)
+			}
+			Notice(r.Context()).Str("logger", name).Str("level", level.String()).Str("ttl", body.TTL).Msg("log level changed")
+		case http.MethodDelete:
+			if name == "" {
+				http.Error(w, "logger is required", http.StatusBadRequest)
+				return
+			}
+			ResetNamedLevel(name)
+			Notice(r.Context()).Str("logger", name).Msg("log level override removed")
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(currentLevel(name))
+	})
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLevelHandler(t *testing.T) {
+	SetLevel(zerolog.InfoLevel)
+	defer SetLevel(zerolog.DebugLevel)
+	h := LevelHandler()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		level  string
+	}{
+		{name: "get", method: http.MethodGet, status: http.StatusOK, level: "info"},
+		{name: "put", method: http.MethodPut, body: `{"level":"warn"}`, status: http.StatusOK, level: "warn"},
+		{name: "unknown level", method: http.MethodPut, body: `{"level":"verbose"}`, status: http.StatusBadRequest},
+		{name: "bad ttl", method: http.MethodPut, body: `{"level":"debug","ttl":"1x"}`, status: http.StatusBadRequest},
+		{name: "put named", method: http.MethodPut, target: "/log/level?logger=resty", body: `{"level":"debug"}`, status: http.StatusOK, level: "debug"},
+		{name: "get named", method: http.MethodGet, target: "/log/level?logger=resty.client", status: http.StatusOK, level: "debug"},
+		{name: "delete named", method: http.MethodDelete, target: "/log/level?logger=resty", status: http.StatusOK, level: "warn"},
+		{name: "delete without logger", method: http.MethodDelete, status: http.StatusBadRequest},
+		{name: "method not allowed", method: http.MethodPatch, status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target
+			if target == "" {
+				target = "/log/level"
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, target, strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.level != "" {
+				var body levelBody
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				if body.Level != tt.level {
+					t.Errorf("level = %s, want %s", body.Level, tt.level)
+				}
+			}
+		})
+	}
+}
+
+func TestSetLevel_TTL(t *testing.T) {
+	SetLevel(zerolog.InfoLevel)
+	defer SetLevel(zerolog.DebugLevel)
+	SetLevel(zerolog.DebugLevel, 20*time.Millisecond)
+	if Level() != zerolog.DebugLevel {
+		t.Fatalf("Level() = %v, want %v", Level(), zerolog.DebugLevel)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if Level() != zerolog.InfoLevel {
+		t.Errorf("Level() = %v, want %v", Level(), zerolog.InfoLevel)
+	}
+}
+
+func TestNamed(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetGlobalOut(buf)
+	SetLevel(zerolog.InfoLevel)
+	defer SetLevel(zerolog.DebugLevel)
+	for name, level := range ParseLevelOverrides("resty=debug, ginlog=warn,invalid") {
+		SetNamedLevel(name, level)
+	}
+	defer ResetNamedLevel("resty")
+	defer ResetNamedLevel("ginlog")
+	if zerolog.GlobalLevel() != zerolog.InfoLevel {
+		t.Errorf("zerolog.GlobalLevel() = %v, want %v", zerolog.GlobalLevel(), zerolog.InfoLevel)
+	}
+
+	tests := []struct {
+		name    string
+		logger  *Logger
+		event   func(l *Logger) *zerolog.Event
+		written bool
+	}{
+		{name: "default debug", logger: DefaultLogger(), event: func(l *Logger) *zerolog.Event { return l.Debug(context.Background()) }},
+		{name: "resty debug", logger: Named("resty"), event: func(l *Logger) *zerolog.Event { return l.Debug(context.Background()) }, written: true},
+		{name: "resty child debug", logger: Named("resty").Named("client"), event: func(l *Logger) *zerolog.Event { return l.Debug(context.Background()) }, written: true},
+		{name: "ginlog info", logger: Named("ginlog"), event: func(l *Logger) *zerolog.Event { return l.Info(context.Background()) }},
+		{name: "ginlog warn", logger: Named("ginlog"), event: func(l *Logger) *zerolog.Event { return l.Warn(context.Background()) }, written: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.event(tt.logger).Msg("test")
+			if written := buf.Len() > 0; written != tt.written {
+				t.Fatalf("written = %v, want %v", written, tt.written)
+			}
+			if tt.written && !strings.Contains(buf.String(), `"logger":"`+tt.logger.Name()+`"`) {
+				t.Errorf("logger name not found: %s", buf.String())
+			}
+			if tt.written && !strings.Contains(buf.String(), `"level":"`) {
+				t.Errorf("level not found: %s", buf.String())
+			}
+		})
+	}
+}
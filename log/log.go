@@ -6,111 +6,79 @@ import (
 	"os"
 	"time"
 
-	"github.com/goccha/logging/tracing"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 func init() {
+	SetDefault(New())
 	SetGlobalOut(getWriter())
 	SetGlobalErr(getErrorWriter())
 	level, ok := os.LookupEnv("LOG_LEVEL")
 	if !ok {
 		level = "debug"
 	}
-	if len(level) > 0 {
-		switch level {
-		case "trace":
-			zerolog.SetGlobalLevel(zerolog.TraceLevel)
-		case "debug":
-			zerolog.SetGlobalLevel(zerolog.DebugLevel)
-		case "info":
-			zerolog.SetGlobalLevel(zerolog.InfoLevel)
-		case "warn":
-			zerolog.SetGlobalLevel(zerolog.WarnLevel)
-		case "error":
-			zerolog.SetGlobalLevel(zerolog.ErrorLevel)
-		case "fatal":
-			zerolog.SetGlobalLevel(zerolog.FatalLevel)
-		case "panic":
-			zerolog.SetGlobalLevel(zerolog.PanicLevel)
-		case "disabled":
-			zerolog.SetGlobalLevel(zerolog.Disabled)
-		default:
-			zerolog.SetGlobalLevel(zerolog.NoLevel)
+	lvl, _ := ParseLevel(level)
+	SetLevel(lvl)
+	if v, ok := os.LookupEnv("LOG_LEVEL_OVERRIDES"); ok {
+		for name, lvl := range ParseLevelOverrides(v) {
+			SetNamedLevel(name, lvl)
 		}
-	} else {
-		zerolog.SetGlobalLevel(zerolog.NoLevel)
 	}
 	zerolog.TimeFieldFormat = time.RFC3339Nano
 }
 
 func SetGlobalOut(w io.Writer) {
+	SetDefault(DefaultLogger().With(WithOut(w)))
 	log.Logger = zerolog.New(w).With().Timestamp().Logger()
 }
 
-var errorLogger zerolog.Logger
-
 func SetGlobalErr(w io.Writer) {
-	errorLogger = zerolog.New(w).With().Caller().Timestamp().Logger()
+	SetDefault(DefaultLogger().With(WithErr(w)))
 }
 
 func Default(ctx context.Context) *zerolog.Event {
-	return tracing.WithTrace(ctx, log.Trace()).Str("severity", "DEFAULT")
+	return DefaultLogger().Default(ctx)
 }
 
 func Trace(ctx context.Context) *zerolog.Event {
-	return tracing.WithTrace(ctx, log.Trace()).Str("severity", "TRACE")
+	return DefaultLogger().Trace(ctx)
 }
 
 func Debug(ctx context.Context) *zerolog.Event {
-	return tracing.WithTrace(ctx, log.Debug()).Str("severity", "DEBUG")
+	return DefaultLogger().Debug(ctx)
 }
 
 func Info(ctx context.Context) *zerolog.Event {
-	return tracing.WithTrace(ctx, log.Info()).Str("severity", "INFO")
+	return DefaultLogger().Info(ctx)
 }
 
 func Notice(ctx context.Context) *zerolog.Event {
-	return tracing.WithTrace(ctx, log.Info()).Str("severity", "NOTICE")
+	return DefaultLogger().Notice(ctx)
 }
 
 func Warn(ctx context.Context, skip ...int) *zerolog.Event {
-	logger := skipLogger(log.Logger, skip...)
-	return tracing.WithTrace(ctx, logger.Warn()).Str("severity", "WARNING")
+	return DefaultLogger().Warn(ctx, skip...)
 }
 
 func Error(ctx context.Context, skip ...int) *zerolog.Event {
-	logger := skipLogger(errorLogger, skip...)
-	return tracing.WithTrace(ctx, logger.Error()).Str("severity", "ERROR")
+	return DefaultLogger().Error(ctx, skip...)
 }
 
 func Fatal(ctx context.Context, skip ...int) *zerolog.Event {
-	logger := skipLogger(errorLogger, skip...)
-	return tracing.WithTrace(ctx, logger.Error()).Str("severity", "CRITICAL")
+	return DefaultLogger().Fatal(ctx, skip...)
 }
 
 func Critical(ctx context.Context, skip ...int) *zerolog.Event {
-	logger := skipLogger(errorLogger, skip...)
-	return tracing.WithTrace(ctx, logger.Error()).Str("severity", "CRITICAL")
+	return DefaultLogger().Critical(ctx, skip...)
 }
 
 func Alert(ctx context.Context, skip ...int) *zerolog.Event {
-	logger := skipLogger(errorLogger, skip...)
-	return tracing.WithTrace(ctx, logger.Error()).Str("severity", "ALERT")
+	return DefaultLogger().Alert(ctx, skip...)
 }
 
 func Emergency(ctx context.Context, skip ...int) *zerolog.Event {
-	logger := skipLogger(errorLogger, skip...)
-	return tracing.WithTrace(ctx, logger.Error()).Str("severity", "EMERGENCY")
-}
-
-func skipLogger(logger zerolog.Logger, skip ...int) zerolog.Logger {
-	if len(skip) > 0 {
-		skipCount := zerolog.CallerSkipFrameCount + skip[0]
-		logger = zerolog.New(os.Stderr).With().CallerWithSkipFrameCount(skipCount).Timestamp().Logger()
-	}
-	return logger
+	return DefaultLogger().Emergency(ctx, skip...)
 }
 
 type objectKey struct{}
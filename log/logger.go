@@ -0,0 +1,345 @@
+package log
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/goccha/logging/tracing"
+	"github.com/rs/zerolog"
+)
+
+type config struct {
+	out      io.Writer
+	err      io.Writer
+	level    zerolog.Level
+	fields   map[string]any
+	sinks    []Sink
+	sinkOnly bool
+	sampler  *Sampler
+}
+
+type Option func(c *config)
+
+// WithOut sets the writer for DEFAULT to WARNING events.
+func WithOut(w io.Writer) Option {
+	return func(c *config) {
+		c.out = w
+	}
+}
+
+// WithErr sets the writer for ERROR and above.
+func WithErr(w io.Writer) Option {
+	return func(c *config) {
+		c.err = w
+	}
+}
+
+// WithLevel sets the minimum level of the logger.
+// The global level set by LOG_LEVEL still applies.
+func WithLevel(level zerolog.Level) Option {
+	return func(c *config) {
+		c.level = level
+	}
+}
+
+// WithFields adds fields to every event written by the logger.
+func WithFields(fields Object) Option {
+	return func(c *config) {
+		if c.fields == nil {
+			c.fields = make(map[string]any, len(fields))
+		}
+		for k, v := range fields {
+			c.fields[k] = v
+		}
+	}
+}
+
+// NameFieldName is the field name used for the name of a named logger.
+var NameFieldName = "logger"
+
+type Logger struct {
+	cfg      config
+	name     string
+	inherit  bool
+	out      zerolog.Logger
+	errOut   zerolog.Logger
+	errorLog zerolog.Logger
+}
+
+func New(opts ...Option) *Logger {
+	return newLogger(config{
+		out:   os.Stdout,
+		err:   os.Stderr,
+		level: zerolog.TraceLevel,
+	}, opts...)
+}
+
+func newLogger(cfg config, opts ...Option) *Logger {
+	if cfg.fields != nil {
+		fields := make(map[string]any, len(cfg.fields))
+		for k, v := range cfg.fields {
+			fields[k] = v
+		}
+		cfg.fields = fields
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	l := &Logger{cfg: cfg}
+	if cfg.sampler != nil {
+		cfg.sampler.attach(cfg.out)
+	}
+	l.out = l.base(cfg.out).Timestamp().Logger()
+	l.errOut = l.base(cfg.err).Timestamp().Logger()
+	l.errorLog = l.base(cfg.err).Caller().Timestamp().Logger()
+	return l
+}
+
+func (l *Logger) base(w io.Writer) zerolog.Context {
+	c := zerolog.New(w).Level(l.cfg.level).With()
+	if len(l.cfg.fields) > 0 {
+		c = c.Fields(l.cfg.fields)
+	}
+	return c
+}
+
+// With returns a copy of the logger with the options applied.
+func (l *Logger) With(opts ...Option) *Logger {
+	w := newLogger(l.backend().cfg, opts...)
+	w.name = l.name
+	return w
+}
+
+// Named returns a logger that follows the default logger and writes its events with the given name.
+// Its level can be overridden by LOG_LEVEL_OVERRIDES or SetNamedLevel.
+func Named(name string) *Logger {
+	return &Logger{name: name, inherit: true}
+}
+
+// Named returns a copy of the logger with the given name.
+// If the logger is already named, the name is appended to it separated by a dot.
+func (l *Logger) Named(name string) *Logger {
+	n := *l
+	if n.name != "" {
+		n.name += "." + name
+	} else {
+		n.name = name
+	}
+	return &n
+}
+
+func (l *Logger) Name() string {
+	return l.name
+}
+
+func (l *Logger) backend() *Logger {
+	if l.inherit {
+		return DefaultLogger()
+	}
+	return l
+}
+
+// Enabled reports whether events of the level are written by the logger.
+func (l *Logger) Enabled(level zerolog.Level) bool {
+	return l.enabled(level) && level >= l.backend().out.GetLevel()
+}
+
+func (l *Logger) enabled(level zerolog.Level) bool {
+	return level >= levels.Load().level(l.name)
+}
+
+func (l *Logger) newEvent(ctx context.Context, logger *zerolog.Logger, level zerolog.Level, severity string) *zerolog.Event {
+	return l.event(ctx, logger, level, severity, level >= zerolog.ErrorLevel)
+}
+
+// event returns an event written to the writer of WithErr if errOut is true, or of WithOut otherwise.
+func (l *Logger) event(ctx context.Context, logger *zerolog.Logger, level zerolog.Level, severity string, errOut bool) *zerolog.Event {
+	if !l.enabled(level) {
+		return nil
+	}
+	cfg := &l.backend().cfg
+	var w io.Writer
+	if len(cfg.sinks) > 0 {
+		sw := &sinkWriter{ctx: ctx, sinks: cfg.sinks}
+		if !cfg.sinkOnly {
+			sw.out = cfg.out
+			if errOut {
+				sw.out = cfg.err
+			}
+		}
+		w = sw
+	}
+	var e *zerolog.Event
+	if level < zerolog.GlobalLevel() {
+		// a named override below zerolog's global level, which would drop the event
+		if level < logger.GetLevel() {
+			return nil
+		}
+		if w == nil {
+			w = cfg.out
+			if errOut {
+				w = cfg.err
+			}
+		}
+		sl := logger.Output(levelWriter{w: w, level: level})
+		if cfg.sampler != nil {
+			// zerolog passes NoLevel to the hooks of events sent with Log
+			sl = sl.Hook(sampleHook{sampler: cfg.sampler, level: level, severity: severity})
+		}
+		e = sl.Log().Str(zerolog.LevelFieldName, zerolog.LevelFieldMarshalFunc(level))
+	} else {
+		if w != nil || cfg.sampler != nil {
+			sl := *logger
+			if w != nil {
+				sl = sl.Output(w)
+			}
+			if cfg.sampler != nil {
+				sl = sl.Hook(sampleHook{sampler: cfg.sampler, level: level, severity: severity})
+			}
+			logger = &sl
+		}
+		e = logger.WithLevel(level)
+	}
+	e = tracing.WithTrace(ctx, e).Str("severity", severity)
+	if l.name != "" {
+		e = e.Str(NameFieldName, l.name)
+	}
+	return e
+}
+
+// levelWriter writes events with the level they were created for.
+type levelWriter struct {
+	w     io.Writer
+	level zerolog.Level
+}
+
+func (w levelWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(w.level, p)
+}
+
+func (w levelWriter) WriteLevel(_ zerolog.Level, p []byte) (int, error) {
+	if lw, ok := w.w.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(w.level, p)
+	}
+	return w.w.Write(p)
+}
+
+type loggerKey struct{}
+
+// WithContext returns a copy of ctx in which the logger is stored.
+func (l *Logger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// Ctx returns the logger stored in ctx, or the default logger if there is none.
+func Ctx(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return DefaultLogger()
+}
+
+var std atomic.Pointer[Logger]
+
+func DefaultLogger() *Logger {
+	return std.Load()
+}
+
+// SetDefault replaces the logger used by the package functions.
+func SetDefault(l *Logger) {
+	std.Store(l)
+}
+
+func (l *Logger) Default(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, &l.backend().out, zerolog.TraceLevel, "DEFAULT")
+}
+
+func (l *Logger) Trace(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, &l.backend().out, zerolog.TraceLevel, "TRACE")
+}
+
+func (l *Logger) Debug(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, &l.backend().out, zerolog.DebugLevel, "DEBUG")
+}
+
+func (l *Logger) Info(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, &l.backend().out, zerolog.InfoLevel, "INFO")
+}
+
+func (l *Logger) Notice(ctx context.Context) *zerolog.Event {
+	return l.newEvent(ctx, &l.backend().out, zerolog.InfoLevel, "NOTICE")
+}
+
+func (l *Logger) Warn(ctx context.Context, skip ...int) *zerolog.Event {
+	logger := l.backend().out
+	if len(skip) > 0 {
+		logger = skipLogger(logger, skip[0])
+	}
+	return l.newEvent(ctx, &logger, zerolog.WarnLevel, "WARNING")
+}
+
+func (l *Logger) Error(ctx context.Context, skip ...int) *zerolog.Event {
+	return l.newEvent(ctx, l.backend().errorLogger(skip...), zerolog.ErrorLevel, "ERROR")
+}
+
+func (l *Logger) Fatal(ctx context.Context, skip ...int) *zerolog.Event {
+	return l.newEvent(ctx, l.backend().errorLogger(skip...), zerolog.ErrorLevel, "CRITICAL")
+}
+
+func (l *Logger) Critical(ctx context.Context, skip ...int) *zerolog.Event {
+	return l.newEvent(ctx, l.backend().errorLogger(skip...), zerolog.ErrorLevel, "CRITICAL")
+}
+
+func (l *Logger) Alert(ctx context.Context, skip ...int) *zerolog.Event {
+	return l.newEvent(ctx, l.backend().errorLogger(skip...), zerolog.ErrorLevel, "ALERT")
+}
+
+func (l *Logger) Emergency(ctx context.Context, skip ...int) *zerolog.Event {
+	return l.newEvent(ctx, l.backend().errorLogger(skip...), zerolog.ErrorLevel, "EMERGENCY")
+}
+
+// WithLevel returns an event of the level written to the writer of WithOut without the caller,
+// so that the level only changes the severity, as for access logs.
+// FatalLevel and PanicLevel are written as CRITICAL without exiting or panicking.
+func (l *Logger) WithLevel(ctx context.Context, level zerolog.Level) *zerolog.Event {
+	var severity string
+	switch level {
+	case zerolog.TraceLevel:
+		severity = "TRACE"
+	case zerolog.DebugLevel:
+		severity = "DEBUG"
+	case zerolog.InfoLevel:
+		severity = "INFO"
+	case zerolog.WarnLevel:
+		severity = "WARNING"
+	case zerolog.ErrorLevel:
+		severity = "ERROR"
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		level, severity = zerolog.ErrorLevel, "CRITICAL"
+	default:
+		return nil
+	}
+	return l.event(ctx, &l.backend().out, level, severity, false)
+}
+
+func (l *Logger) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
+	return Dump(ctx, log)
+}
+
+func (l *Logger) EmbedObject(ctx context.Context, event *zerolog.Event) *zerolog.Event {
+	return EmbedObject(ctx, event)
+}
+
+func (l *Logger) errorLogger(skip ...int) *zerolog.Logger {
+	if len(skip) > 0 {
+		logger := skipLogger(l.errOut, skip[0])
+		return &logger
+	}
+	return &l.errorLog
+}
+
+func skipLogger(logger zerolog.Logger, skip int) zerolog.Logger {
+	return logger.With().CallerWithSkipFrameCount(zerolog.CallerSkipFrameCount + skip).Logger()
+}
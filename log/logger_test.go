@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLogger(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := New(WithOut(out), WithErr(errOut), WithLevel(zerolog.InfoLevel), WithFields(Object{"component": "worker"}))
+	ctx := logger.WithContext(context.Background())
+
+	Ctx(ctx).Debug(ctx).Msg("debug")
+	if out.Len() != 0 {
+		t.Errorf("debug event written: %s", out.String())
+	}
+	Ctx(ctx).Info(ctx).Msg("info")
+	m := make(map[string]any)
+	if err := json.Unmarshal(out.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["component"] != "worker" || m["severity"] != "INFO" {
+		t.Errorf("unexpected event: %v", m)
+	}
+	Ctx(ctx).Error(ctx).Msg("error")
+	m = make(map[string]any)
+	if err := json.Unmarshal(errOut.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["component"] != "worker" || m["severity"] != "ERROR" || m[zerolog.CallerFieldName] == nil {
+		t.Errorf("unexpected event: %v", m)
+	}
+	if Ctx(context.Background()) != DefaultLogger() {
+		t.Error("Ctx() should return the default logger")
+	}
+}
+
+func TestLogger_WithLevel(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	logger := New(WithOut(out), WithErr(errOut))
+	logger.WithLevel(context.Background(), zerolog.ErrorLevel).Msg("error")
+	if errOut.Len() != 0 {
+		t.Errorf("written to the error writer: %s", errOut.String())
+	}
+	m := make(map[string]any)
+	if err := json.Unmarshal(out.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["severity"] != "ERROR" || m["level"] != "error" || m[zerolog.CallerFieldName] != nil {
+		t.Errorf("unexpected event: %v", m)
+	}
+	if logger.WithLevel(context.Background(), zerolog.Disabled) != nil {
+		t.Error("disabled event is returned")
+	}
+}
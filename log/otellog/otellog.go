@@ -0,0 +1,151 @@
+package otellog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/goccha/logging/log"
+	"github.com/rs/zerolog"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+const ScopeName = "github.com/goccha/logging/log"
+
+var severities = map[string]otellog.Severity{
+	"DEFAULT":   otellog.SeverityUndefined,
+	"TRACE":     otellog.SeverityTrace,
+	"DEBUG":     otellog.SeverityDebug,
+	"INFO":      otellog.SeverityInfo,
+	"NOTICE":    otellog.SeverityInfo2,
+	"WARNING":   otellog.SeverityWarn,
+	"ERROR":     otellog.SeverityError,
+	"CRITICAL":  otellog.SeverityFatal,
+	"ALERT":     otellog.SeverityFatal2,
+	"EMERGENCY": otellog.SeverityFatal4,
+}
+
+// Severity converts a severity of the log package to the OpenTelemetry SeverityNumber.
+func Severity(severity string) otellog.Severity {
+	return severities[severity]
+}
+
+type Option func(s *Sink)
+
+// WithLoggerProvider sets the provider of the OpenTelemetry logger.
+// The default is the global provider.
+func WithLoggerProvider(provider otellog.LoggerProvider) Option {
+	return func(s *Sink) {
+		s.provider = provider
+	}
+}
+
+// WithScope sets the instrumentation scope name of the records.
+func WithScope(name string, opts ...otellog.LoggerOption) Option {
+	return func(s *Sink) {
+		s.scope = name
+		s.options = opts
+	}
+}
+
+// Sink is a log.Sink that emits events as OpenTelemetry log records.
+// The trace and span IDs of the records are taken from the context of the event.
+type Sink struct {
+	provider otellog.LoggerProvider
+	scope    string
+	options  []otellog.LoggerOption
+	logger   otellog.Logger
+}
+
+func New(opts ...Option) *Sink {
+	s := &Sink{scope: ScopeName}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.provider == nil {
+		s.provider = global.GetLoggerProvider()
+	}
+	s.logger = s.provider.Logger(s.scope, s.options...)
+	return s
+}
+
+// Setup adds a Sink to the default logger.
+// With only, events are written only as OpenTelemetry log records.
+func Setup(only bool, opts ...Option) *Sink {
+	s := New(opts...)
+	options := []log.Option{log.WithSink(s)}
+	if only {
+		options = append(options, log.SinkOnly())
+	}
+	log.SetDefault(log.DefaultLogger().With(options...))
+	return s
+}
+
+func (s *Sink) WriteEvent(ctx context.Context, level zerolog.Level, p []byte) error {
+	fields := make(map[string]any)
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&fields); err != nil {
+		return err
+	}
+	var r otellog.Record
+	now := time.Now()
+	r.SetTimestamp(now)
+	r.SetObservedTimestamp(now)
+	if v, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if t, err := time.Parse(zerolog.TimeFieldFormat, v); err == nil {
+			r.SetTimestamp(t)
+		}
+	}
+	severity, _ := fields["severity"].(string)
+	if severity == "" {
+		severity = level.String()
+	}
+	r.SetSeverityText(severity)
+	r.SetSeverity(Severity(severity))
+	if msg, ok := fields[zerolog.MessageFieldName].(string); ok {
+		r.SetBody(otellog.StringValue(msg))
+	}
+	delete(fields, zerolog.TimestampFieldName)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.MessageFieldName)
+	delete(fields, "severity")
+	attrs := make([]otellog.KeyValue, 0, len(fields))
+	for k, v := range fields {
+		attrs = append(attrs, otellog.KeyValue{Key: k, Value: value(v)})
+	}
+	r.AddAttributes(attrs...)
+	s.logger.Emit(ctx, r)
+	return nil
+}
+
+func value(v any) otellog.Value {
+	switch v := v.(type) {
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return otellog.Int64Value(i)
+		}
+		f, _ := v.Float64()
+		return otellog.Float64Value(f)
+	case []any:
+		values := make([]otellog.Value, 0, len(v))
+		for _, e := range v {
+			values = append(values, value(e))
+		}
+		return otellog.SliceValue(values...)
+	case map[string]any:
+		kvs := make([]otellog.KeyValue, 0, len(v))
+		for k, e := range v {
+			kvs = append(kvs, otellog.KeyValue{Key: k, Value: value(e)})
+		}
+		return otellog.MapValue(kvs...)
+	default:
+		return otellog.Value{}
+	}
+}
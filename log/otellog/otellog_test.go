@@ -0,0 +1,114 @@
+package otellog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector is an in-process stand-in for an OTLP/HTTP collector.
+type collector struct {
+	mu      sync.Mutex
+	records []*logspb.LogRecord
+	service string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &collogs.ExportLogsServiceRequest{}
+	if err = proto.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	for _, rl := range req.ResourceLogs {
+		for _, attr := range rl.Resource.Attributes {
+			if attr.Key == string(semconv.ServiceNameKey) {
+				c.service = attr.Value.GetStringValue()
+			}
+		}
+		for _, sl := range rl.ScopeLogs {
+			c.records = append(c.records, sl.LogRecords...)
+		}
+	}
+	c.mu.Unlock()
+	data, _ := proto.Marshal(&collogs.ExportLogsServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(data)
+}
+
+func TestSink(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	ctx := context.Background()
+	tb := tracing.NewTracer().WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("test-service")))
+	lp, err := NewLoggerProvider(ctx, WithTracerResource(tb), WithHttpExporter(otlploghttp.WithEndpointURL(srv.URL+"/v1/logs")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(ctx, "test")
+
+	buf := &bytes.Buffer{}
+	logger := log.New(log.WithOut(buf), log.WithSink(New(WithLoggerProvider(lp))))
+	logger.Notice(ctx).Str("user", "test").Int("count", 3).Msg("hello")
+	span.End()
+	if err = lp.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("event was not written to the writer")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.records) != 1 {
+		t.Fatalf("records = %d, want 1", len(c.records))
+	}
+	r := c.records[0]
+	if r.Body.GetStringValue() != "hello" {
+		t.Errorf("body = %v, want hello", r.Body)
+	}
+	if r.SeverityText != "NOTICE" || r.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_INFO2 {
+		t.Errorf("severity = %s(%d)", r.SeverityText, r.SeverityNumber)
+	}
+	traceId, spanId := span.SpanContext().TraceID(), span.SpanContext().SpanID()
+	if !bytes.Equal(r.TraceId, traceId[:]) || !bytes.Equal(r.SpanId, spanId[:]) {
+		t.Errorf("trace = %x/%x, want %s/%s", r.TraceId, r.SpanId, traceId, spanId)
+	}
+	attrs := make(map[string]any)
+	for _, kv := range r.Attributes {
+		if _, ok := kv.Value.Value.(*commonpb.AnyValue_StringValue); ok {
+			attrs[kv.Key] = kv.Value.GetStringValue()
+		} else {
+			attrs[kv.Key] = kv.Value.GetIntValue()
+		}
+	}
+	if attrs["user"] != "test" || attrs["count"] != int64(3) {
+		t.Errorf("attributes = %v", attrs)
+	}
+	if c.service != "test-service" {
+		t.Errorf("service = %s, want test-service", c.service)
+	}
+}
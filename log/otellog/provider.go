@@ -0,0 +1,87 @@
+package otellog
+
+import (
+	"context"
+
+	"github.com/goccha/envar"
+	"github.com/goccha/logging/tracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// NewLoggerProvider creates a LoggerProvider and sets it as the global provider.
+func NewLoggerProvider(ctx context.Context, options ...LoggerProviderOption) (*sdklog.LoggerProvider, error) {
+	opts, err := LoggerProviderOptions(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	lp := sdklog.NewLoggerProvider(opts...)
+	global.SetLoggerProvider(lp)
+	return lp, nil
+}
+
+type LoggerProviderOption func(ctx context.Context) (sdklog.LoggerProviderOption, error)
+
+func LoggerProviderOptions(ctx context.Context, options ...LoggerProviderOption) ([]sdklog.LoggerProviderOption, error) {
+	opts := make([]sdklog.LoggerProviderOption, 0, len(options))
+	for _, option := range options {
+		opt, err := option(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
+
+func WithResource(res *resource.Resource) LoggerProviderOption {
+	return func(ctx context.Context) (sdklog.LoggerProviderOption, error) {
+		return sdklog.WithResource(res), nil
+	}
+}
+
+// WithTracerResource shares the resource of the tracer provider built by b.
+// See tracing.TracerBuilder.ResolveResource.
+func WithTracerResource(b *tracing.TracerBuilder) LoggerProviderOption {
+	return func(ctx context.Context) (sdklog.LoggerProviderOption, error) {
+		return sdklog.WithResource(b.ResolveResource()), nil
+	}
+}
+
+// WithExporter exports records through the given exporter with a batch processor.
+func WithExporter(exporter sdklog.Exporter) LoggerProviderOption {
+	return func(ctx context.Context) (sdklog.LoggerProviderOption, error) {
+		return sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)), nil
+	}
+}
+
+func WithGrpcExporter(opts ...otlploggrpc.Option) LoggerProviderOption {
+	return func(ctx context.Context) (sdklog.LoggerProviderOption, error) {
+		endpoint := envar.Get("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT").String("0.0.0.0:4317")
+		options := make([]otlploggrpc.Option, 0, len(opts)+2)
+		options = append(options, otlploggrpc.WithInsecure(), otlploggrpc.WithEndpoint(endpoint))
+		options = append(options, opts...)
+		exporter, err := otlploggrpc.New(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		return sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)), nil
+	}
+}
+
+func WithHttpExporter(opts ...otlploghttp.Option) LoggerProviderOption {
+	return func(ctx context.Context) (sdklog.LoggerProviderOption, error) {
+		endpoint := envar.Get("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT").String("0.0.0.0:4318")
+		options := make([]otlploghttp.Option, 0, len(opts)+2)
+		options = append(options, otlploghttp.WithInsecure(), otlploghttp.WithEndpoint(endpoint))
+		options = append(options, opts...)
+		exporter, err := otlploghttp.New(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		return sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)), nil
+	}
+}
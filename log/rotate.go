@@ -0,0 +1,345 @@
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	megabyte         = 1024 * 1024
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	defaultFileMode  = 0o644
+	defaultFileFlags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	defaultDirectory = 0o755
+)
+
+type RotateOption func(w *RotateWriter)
+
+// RotateMaxSize rotates the file when it grows beyond the given size in megabytes.
+func RotateMaxSize(megabytes int) RotateOption {
+	return func(w *RotateWriter) {
+		w.maxSize = int64(megabytes) * megabyte
+	}
+}
+
+// RotateDaily rotates the file when the date changes.
+func RotateDaily() RotateOption {
+	return func(w *RotateWriter) {
+		w.daily = true
+	}
+}
+
+// RotateMaxBackups sets the number of rotated files to keep.
+func RotateMaxBackups(n int) RotateOption {
+	return func(w *RotateWriter) {
+		w.maxBackups = n
+	}
+}
+
+// RotateMaxAge removes rotated files older than the given duration.
+func RotateMaxAge(d time.Duration) RotateOption {
+	return func(w *RotateWriter) {
+		w.maxAge = d
+	}
+}
+
+// RotateCompress compresses rotated files with gzip.
+func RotateCompress() RotateOption {
+	return func(w *RotateWriter) {
+		w.compress = true
+	}
+}
+
+// ReopenOnSignal reopens the file when one of the signals (SIGHUP by default) is received,
+// so that the file can be rotated by an external tool such as logrotate.
+func ReopenOnSignal(sig ...os.Signal) RotateOption {
+	return func(w *RotateWriter) {
+		if len(sig) == 0 {
+			sig = []os.Signal{syscall.SIGHUP}
+		}
+		w.signals = sig
+	}
+}
+
+// RotateWriter is an io.Writer that writes to a file and rotates it by size and date.
+// Rotated files are renamed to name-<timestamp>.ext in the same directory.
+type RotateWriter struct {
+	filename   string
+	maxSize    int64
+	daily      bool
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	signals    []os.Signal
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+	size   int64
+	opened time.Time
+	millMu sync.Mutex
+	mills  sync.WaitGroup
+	sigCh  chan os.Signal
+	now    func() time.Time
+}
+
+func NewRotateWriter(filename string, opts ...RotateOption) (*RotateWriter, error) {
+	w := &RotateWriter{
+		filename: filename,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	if len(w.signals) > 0 {
+		w.sigCh = make(chan os.Signal, 1)
+		signal.Notify(w.sigCh, w.signals...)
+		go func(ch chan os.Signal) {
+			for range ch {
+				if err := w.Reopen(); err != nil && !errors.Is(err, os.ErrClosed) {
+					fmt.Fprintf(os.Stderr, "log: could not reopen %s: %v\n", w.filename, err)
+				}
+			}
+		}(w.sigCh)
+	}
+	return w, nil
+}
+
+// Write writes p to the file. It returns os.ErrClosed after Close.
+func (w *RotateWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.file == nil {
+		if err = w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.shouldRotate(int64(len(p))) {
+		if err = w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotateWriter) shouldRotate(n int64) bool {
+	if w.maxSize > 0 && w.size > 0 && w.size+n > w.maxSize {
+		return true
+	}
+	if w.daily {
+		y1, m1, d1 := w.opened.Date()
+		y2, m2, d2 := w.now().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+// Rotate closes the current file, renames it and opens a new one.
+func (w *RotateWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file without renaming it.
+func (w *RotateWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// Close closes the file and waits for the rotated files being compressed or removed.
+// Writes after Close return os.ErrClosed.
+func (w *RotateWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	if w.sigCh != nil {
+		signal.Stop(w.sigCh)
+		close(w.sigCh)
+		w.sigCh = nil
+	}
+	err := w.close()
+	w.mu.Unlock()
+	w.mills.Wait()
+	return err
+}
+
+func (w *RotateWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RotateWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.filename), defaultDirectory); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.filename, defaultFileFlags, defaultFileMode)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	w.opened = w.now()
+	if w.size > 0 {
+		w.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (w *RotateWriter) rotate() error {
+	if err := w.close(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(w.filename); err == nil {
+		if err = os.Rename(w.filename, w.backupName(w.opened)); err != nil {
+			return err
+		}
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	if w.compress || w.maxBackups > 0 || w.maxAge > 0 {
+		w.mills.Add(1)
+		go func() {
+			defer w.mills.Done()
+			w.mill()
+		}()
+	}
+	return nil
+}
+
+func (w *RotateWriter) backupName(t time.Time) string {
+	dir := filepath.Dir(w.filename)
+	ext := filepath.Ext(w.filename)
+	prefix := strings.TrimSuffix(filepath.Base(w.filename), ext)
+	name := filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, t.Format(backupTimeFormat), ext))
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			if _, err = os.Stat(name + compressSuffix); os.IsNotExist(err) {
+				return name
+			}
+		}
+		name = filepath.Join(dir, fmt.Sprintf("%s-%s.%d%s", prefix, t.Format(backupTimeFormat), i, ext))
+	}
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+func (w *RotateWriter) backups() ([]backupFile, error) {
+	dir := filepath.Dir(w.filename)
+	ext := filepath.Ext(w.filename)
+	prefix := strings.TrimSuffix(filepath.Base(w.filename), ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]backupFile, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimPrefix(name, prefix)
+		ts = strings.TrimSuffix(strings.TrimSuffix(ts, compressSuffix), ext)
+		if len(ts) > len(backupTimeFormat) {
+			ts = ts[:len(backupTimeFormat)] // counter suffix
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, ts, time.Local)
+		if err != nil {
+			continue
+		}
+		files = append(files, backupFile{path: filepath.Join(dir, name), time: t})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].time.After(files[j].time)
+	})
+	return files, nil
+}
+
+// mill removes old backups and compresses the remaining ones.
+func (w *RotateWriter) mill() {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+	files, err := w.backups()
+	if err != nil {
+		return
+	}
+	cutoff := w.now().Add(-w.maxAge)
+	for i, f := range files {
+		if (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && f.time.Before(cutoff)) {
+			_ = os.Remove(f.path)
+			continue
+		}
+		if w.compress && !strings.HasSuffix(f.path, compressSuffix) {
+			if err = compressFile(f.path); err != nil {
+				fmt.Fprintf(os.Stderr, "log: could not compress %s: %v\n", f.path, err)
+			}
+		}
+	}
+}
+
+func compressFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	dst, err := os.OpenFile(name+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(name + compressSuffix)
+		return err
+	}
+	return os.Remove(name)
+}
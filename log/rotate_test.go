@@ -0,0 +1,116 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotateWriter_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	w, err := NewRotateWriter(name, RotateMaxSize(1), RotateMaxBackups(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := []byte(strings.Repeat("x", 1023) + "\n")
+	for i := 0; i < 1024*3; i++ {
+		if _, err = w.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	w.mill()
+	files, err := w.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("backups = %d, want 2", len(files))
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > megabyte {
+		t.Errorf("size = %d, want <= %d", info.Size(), megabyte)
+	}
+}
+
+func TestRotateWriter_Daily(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	now := time.Date(2025, 1, 1, 23, 59, 0, 0, time.Local)
+	w, err := NewRotateWriter(name, RotateDaily(), RotateCompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = func() time.Time { return now }
+	w.opened = now
+	if _, err = w.Write([]byte("day1\n")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err = w.Write([]byte("day2\n")); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	files, err := w.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || !strings.HasSuffix(files[0].path, ".log.gz") {
+		t.Fatalf("backups = %v", files)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "day2\n" {
+		t.Errorf("data = %q, want %q", data, "day2\n")
+	}
+}
+
+func TestRotateWriter_Close(t *testing.T) {
+	w, err := NewRotateWriter(filepath.Join(t.TempDir(), "app.log"), ReopenOnSignal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte("after close\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write() error = %v, want %v", err, os.ErrClosed)
+	}
+	if err = w.Reopen(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Reopen() error = %v, want %v", err, os.ErrClosed)
+	}
+	if w.file != nil {
+		t.Error("file reopened after Close")
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(name, []byte("before restart\n"), defaultFileMode); err != nil {
+		t.Fatal(err)
+	}
+	w := openFile(name)
+	if _, err := w.Write([]byte("after restart\n")); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.(io.Closer).Close()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "before restart\nafter restart\n" {
+		t.Errorf("data = %q", data)
+	}
+}
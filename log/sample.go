@@ -0,0 +1,317 @@
+package log
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultSampleWindow         = time.Second
+	defaultSampleReportInterval = 10 * time.Second
+)
+
+type SampleOption func(s *Sampler)
+
+// SampleFirst logs the first n events with the same severity and message in each window.
+func SampleFirst(n int) SampleOption {
+	return func(s *Sampler) {
+		s.first = n
+	}
+}
+
+// SampleThereafter logs every m-th event after the first n in each window.
+// Zero drops all of them.
+func SampleThereafter(m int) SampleOption {
+	return func(s *Sampler) {
+		s.thereafter = m
+	}
+}
+
+// SampleWindow sets the window in which events are counted. The default is a second.
+// The token buckets refilled to full are also evicted at each window.
+func SampleWindow(d time.Duration) SampleOption {
+	return func(s *Sampler) {
+		if d > 0 {
+			s.window = d
+		}
+	}
+}
+
+// SampleRate limits the events logged from each caller location with a token bucket
+// refilled with rate tokens per second and holding up to burst tokens.
+func SampleRate(rate float64, burst int) SampleOption {
+	return func(s *Sampler) {
+		s.rate = rate
+		s.burst = float64(burst)
+		if s.burst < 1 {
+			s.burst = 1
+		}
+	}
+}
+
+// SampleExempt sets the level from which events are never sampled. The default is ERROR.
+func SampleExempt(level zerolog.Level) SampleOption {
+	return func(s *Sampler) {
+		s.exempt = level
+	}
+}
+
+// SampleReportInterval sets how often the number of suppressed events is logged.
+// A non-positive interval disables the report.
+func SampleReportInterval(d time.Duration) SampleOption {
+	return func(s *Sampler) {
+		s.interval = d
+	}
+}
+
+// SampleReportOutput sets the writer of the report.
+// The default is the output of the logger the sampler was last attached to with WithSampler.
+func SampleReportOutput(w io.Writer) SampleOption {
+	return func(s *Sampler) {
+		s.out = w
+	}
+}
+
+type sampleKey struct {
+	severity string
+	msg      string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Sampler is a zerolog.Hook that discards repeated events.
+// Events are counted per severity and message, so that the first n in a window are logged
+// followed by every m-th, and limited per caller location with a token bucket.
+// It is enabled with WithSampler.
+type Sampler struct {
+	first      int
+	thereafter int
+	window     time.Duration
+	rate       float64
+	burst      float64
+	exempt     zerolog.Level
+	interval   time.Duration
+	out        io.Writer
+	now        func() time.Time
+	attached   atomic.Pointer[io.Writer]
+
+	mu         sync.Mutex
+	start      time.Time
+	counts     map[sampleKey]int
+	buckets    map[string]*bucket
+	suppressed uint64
+	stop       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func NewSampler(opts ...SampleOption) *Sampler {
+	s := &Sampler{
+		window:   defaultSampleWindow,
+		exempt:   zerolog.ErrorLevel,
+		interval: defaultSampleReportInterval,
+		now:      time.Now,
+		counts:   make(map[sampleKey]int),
+		buckets:  make(map[string]*bucket),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.start = s.now()
+	go s.run()
+	return s
+}
+
+// WithSampler discards repeated events of the logger with s.
+// The report is written to the output of the logger unless SampleReportOutput is given.
+func WithSampler(s *Sampler) Option {
+	return func(c *config) {
+		c.sampler = s
+	}
+}
+
+// Run samples the event counting it by the name of the level.
+// Loggers created with WithSampler count their events by severity instead.
+func (s *Sampler) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	s.sample(e, level, level.String(), msg)
+}
+
+func (s *Sampler) sample(e *zerolog.Event, level zerolog.Level, severity, msg string) {
+	if level >= s.exempt || level == zerolog.NoLevel || !e.Enabled() {
+		return
+	}
+	var caller string
+	if s.rate > 0 {
+		caller = callerLocation()
+	}
+	if !s.allow(severity, msg, caller) {
+		e.Discard()
+	}
+}
+
+// sampleHook runs the sampler with the level and severity the event was created for.
+type sampleHook struct {
+	sampler  *Sampler
+	level    zerolog.Level
+	severity string
+}
+
+func (h sampleHook) Run(e *zerolog.Event, _ zerolog.Level, msg string) {
+	h.sampler.sample(e, h.level, h.severity, msg)
+}
+
+func (s *Sampler) allow(severity, msg, caller string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.first > 0 || s.thereafter > 0 {
+		if now.Sub(s.start) >= s.window {
+			s.start = now
+			clear(s.counts)
+		}
+		key := sampleKey{severity: severity, msg: msg}
+		n := s.counts[key] + 1
+		s.counts[key] = n
+		if n > s.first && (s.thereafter <= 0 || (n-s.first)%s.thereafter != 0) {
+			s.suppressed++
+			return false
+		}
+	}
+	if s.rate > 0 {
+		b, ok := s.buckets[caller]
+		if !ok {
+			b = &bucket{tokens: s.burst, last: now}
+			s.buckets[caller] = b
+		}
+		b.tokens = min(s.burst, b.tokens+now.Sub(b.last).Seconds()*s.rate)
+		b.last = now
+		if b.tokens < 1 {
+			s.suppressed++
+			return false
+		}
+		b.tokens--
+	}
+	return true
+}
+
+// Suppressed returns the number of events discarded since the last report.
+func (s *Sampler) Suppressed() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suppressed
+}
+
+func (s *Sampler) run() {
+	defer close(s.done)
+	window := time.NewTicker(s.window)
+	defer window.Stop()
+	var reports <-chan time.Time
+	if s.interval > 0 {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		reports = ticker.C
+	}
+	for {
+		select {
+		case <-window.C:
+			s.evict()
+		case <-reports:
+			s.report()
+		case <-s.stop:
+			if s.interval > 0 {
+				s.report()
+			}
+			return
+		}
+	}
+}
+
+// evict removes the buckets refilled to full, which are the same as new ones.
+func (s *Sampler) evict() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for caller, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*s.rate >= s.burst {
+			delete(s.buckets, caller)
+		}
+	}
+}
+
+func (s *Sampler) report() {
+	s.mu.Lock()
+	n := s.suppressed
+	s.suppressed = 0
+	s.mu.Unlock()
+	if n > 0 {
+		logger := zerolog.New(s.output()).With().Timestamp().Logger()
+		logger.Log().Str(zerolog.LevelFieldName, zerolog.WarnLevel.String()).Str("severity", "WARNING").Uint64("suppressed", n).
+			Msg("suppressed " + formatCount(n) + " similar messages")
+	}
+}
+
+// attach sets the output of the logger using the sampler.
+func (s *Sampler) attach(w io.Writer) {
+	s.attached.Store(&w)
+}
+
+func (s *Sampler) output() io.Writer {
+	if s.out != nil {
+		return s.out
+	}
+	if w := s.attached.Load(); w != nil {
+		return *w
+	}
+	return os.Stdout
+}
+
+// Close stops the periodic report after logging the events suppressed since the last one.
+func (s *Sampler) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// callerLocation returns the file and line of the code that sent the event.
+func callerLocation() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/rs/zerolog.") &&
+			!strings.HasPrefix(f.Function, "log/slog.") &&
+			!strings.HasPrefix(f.Function, "github.com/goccha/logging/log.(*SlogHandler).") {
+			return f.File + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func formatCount(n uint64) string {
+	s := strconv.FormatUint(n, 10)
+	var sb strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSampler(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []SampleOption
+		want int
+	}{
+		{name: "first", opts: []SampleOption{SampleFirst(3)}, want: 3},
+		{name: "thereafter", opts: []SampleOption{SampleFirst(2), SampleThereafter(4)}, want: 6},
+		{name: "rate", opts: []SampleOption{SampleRate(0.001, 5)}, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, report := &bytes.Buffer{}, &bytes.Buffer{}
+			opts := append(tt.opts, SampleWindow(time.Hour), SampleReportOutput(report))
+			s := NewSampler(opts...)
+			logger := New(WithOut(buf), WithErr(buf), WithSampler(s))
+			for i := 0; i < 20; i++ {
+				logger.Info(context.Background()).Msg("hello")
+			}
+			logger.Error(context.Background()).Msg("failed")
+			s.Close()
+			if n := strings.Count(buf.String(), "hello"); n != tt.want {
+				t.Errorf("logged %d, want %d", n, tt.want)
+			}
+			if !strings.Contains(buf.String(), "failed") {
+				t.Errorf("error event was sampled")
+			}
+			if !strings.Contains(report.String(), "suppressed "+formatCount(uint64(20-tt.want))+" similar messages") {
+				t.Errorf("report = %s", report.String())
+			}
+		})
+	}
+}
+
+func TestSampler_LoggerOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	s := NewSampler(SampleFirst(1), SampleWindow(time.Hour))
+	logger := New(WithOut(buf), WithSampler(s))
+	for i := 0; i < 3; i++ {
+		logger.Info(context.Background()).Msg("hello")
+	}
+	s.Close()
+	if !strings.Contains(buf.String(), "suppressed 2 similar messages") {
+		t.Errorf("report is not written to the logger output: %s", buf.String())
+	}
+}
+
+func TestSampler_NamedOverride(t *testing.T) {
+	SetLevel(zerolog.InfoLevel)
+	defer SetLevel(zerolog.DebugLevel)
+	SetNamedLevel("sampled", zerolog.DebugLevel)
+	defer ResetNamedLevel("sampled")
+
+	buf := &bytes.Buffer{}
+	s := NewSampler(SampleFirst(1), SampleWindow(time.Hour), SampleReportInterval(0))
+	logger := New(WithOut(buf), WithSampler(s)).Named("sampled")
+	for i := 0; i < 3; i++ {
+		logger.Debug(context.Background()).Msg("hello")
+	}
+	if n := strings.Count(buf.String(), "hello"); n != 1 {
+		t.Errorf("logged %d, want 1", n)
+	}
+	if s.Suppressed() != 2 {
+		t.Errorf("Suppressed() = %d, want 2", s.Suppressed())
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	for n, want := range map[uint64]string{0: "0", 999: "999", 1000: "1,000", 12345: "12,345", 1234567: "1,234,567"} {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestSampler_Severity(t *testing.T) {
+	buf := &bytes.Buffer{}
+	s := NewSampler(SampleFirst(1), SampleWindow(time.Hour), SampleReportInterval(0))
+	defer s.Close()
+	logger := New(WithOut(buf), WithSampler(s))
+	for i := 0; i < 2; i++ {
+		logger.Info(context.Background()).Msg("hello")
+		logger.Notice(context.Background()).Msg("hello")
+	}
+	if n := strings.Count(buf.String(), "hello"); n != 2 {
+		t.Errorf("logged %d, want 2", n)
+	}
+}
+
+func TestSampler_EvictBuckets(t *testing.T) {
+	s := NewSampler(SampleRate(1000, 1), SampleWindow(time.Millisecond), SampleReportInterval(0))
+	defer s.Close()
+	logger := New(WithOut(&bytes.Buffer{}), WithSampler(s))
+	logger.Info(context.Background()).Msg("hello")
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		n := len(s.buckets)
+		s.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("%d buckets are not evicted", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
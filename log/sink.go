@@ -0,0 +1,55 @@
+package log
+
+import (
+	"context"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// Sink receives every event written by a Logger together with the context the event was created with.
+// p is the JSON encoded event and must not be retained after WriteEvent returns.
+type Sink interface {
+	WriteEvent(ctx context.Context, level zerolog.Level, p []byte) error
+}
+
+// WithSink adds a sink to the logger.
+func WithSink(s Sink) Option {
+	return func(c *config) {
+		c.sinks = append(c.sinks[:len(c.sinks):len(c.sinks)], s)
+	}
+}
+
+// SinkOnly writes events only to the sinks and not to the writers of the logger.
+func SinkOnly() Option {
+	return func(c *config) {
+		c.sinkOnly = true
+	}
+}
+
+type sinkWriter struct {
+	ctx   context.Context
+	out   io.Writer
+	sinks []Sink
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *sinkWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err error) {
+	n = len(p)
+	if w.out != nil {
+		if lw, ok := w.out.(zerolog.LevelWriter); ok {
+			n, err = lw.WriteLevel(level, p)
+		} else {
+			n, err = w.out.Write(p)
+		}
+	}
+	for _, s := range w.sinks {
+		if serr := s.WriteEvent(w.ctx, level, p); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return n, err
+}
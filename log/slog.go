@@ -0,0 +1,217 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/rs/zerolog"
+)
+
+// slog levels for the severities that have no counterpart in log/slog.
+const (
+	LevelDefault   = slog.Level(-8)
+	LevelNotice    = slog.Level(2)
+	LevelCritical  = slog.Level(12)
+	LevelAlert     = slog.Level(16)
+	LevelEmergency = slog.Level(20)
+)
+
+// NewSlogHandler returns a slog.Handler that writes records through the default logger,
+// so that every record carries the severity and the trace fields of the context.
+func NewSlogHandler(opts *slog.HandlerOptions) *SlogHandler {
+	return newSlogHandler(nil, opts)
+}
+
+// SlogHandler returns a slog.Handler that writes records through l.
+func (l *Logger) SlogHandler(opts *slog.HandlerOptions) *SlogHandler {
+	return newSlogHandler(l, opts)
+}
+
+func newSlogHandler(l *Logger, opts *slog.HandlerOptions) *SlogHandler {
+	if opts == nil {
+		opts = &slog.HandlerOptions{}
+	}
+	return &SlogHandler{logger: l, opts: *opts}
+}
+
+type SlogHandler struct {
+	logger *Logger
+	opts   slog.HandlerOptions
+	goas   []groupOrAttrs
+}
+
+func (h *SlogHandler) getLogger() *Logger {
+	if h.logger != nil {
+		return h.logger
+	}
+	return DefaultLogger()
+}
+
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.opts.Level != nil && level < h.opts.Level.Level() {
+		return false
+	}
+	return h.getLogger().Enabled(slogLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	e := h.getLogger().slogEvent(ctx, r.Level)
+	if e == nil {
+		return nil
+	}
+	if r.PC != 0 && (h.opts.AddSource || r.Level >= slog.LevelError) {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		e.Str(zerolog.CallerFieldName, zerolog.CallerMarshalFunc(f.PC, f.File, f.Line))
+	}
+	goas := h.goas
+	if r.NumAttrs() == 0 {
+		for len(goas) > 0 && goas[len(goas)-1].group != "" {
+			goas = goas[:len(goas)-1]
+		}
+	}
+	h.appendAttrs(e, nil, goas, r)
+	EmbedObject(ctx, e).Msg(r.Message)
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *SlogHandler) with(goa groupOrAttrs) *SlogHandler {
+	h2 := *h
+	h2.goas = make([]groupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+func (h *SlogHandler) appendAttrs(e *zerolog.Event, groups []string, goas []groupOrAttrs, r slog.Record) {
+	for i, goa := range goas {
+		if goa.group != "" {
+			dict := zerolog.Dict()
+			h.appendAttrs(dict, append(groups[:len(groups):len(groups)], goa.group), goas[i+1:], r)
+			e.Dict(goa.group, dict)
+			return
+		}
+		for _, a := range goa.attrs {
+			h.appendAttr(e, groups, a)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(e, groups, a)
+		return true
+	})
+}
+
+func (h *SlogHandler) appendAttr(e *zerolog.Event, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		e.Str(a.Key, a.Value.String())
+	case slog.KindInt64:
+		e.Int64(a.Key, a.Value.Int64())
+	case slog.KindUint64:
+		e.Uint64(a.Key, a.Value.Uint64())
+	case slog.KindFloat64:
+		e.Float64(a.Key, a.Value.Float64())
+	case slog.KindBool:
+		e.Bool(a.Key, a.Value.Bool())
+	case slog.KindDuration:
+		e.Dur(a.Key, a.Value.Duration())
+	case slog.KindTime:
+		e.Time(a.Key, a.Value.Time())
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key == "" {
+			for _, ga := range attrs {
+				h.appendAttr(e, groups, ga)
+			}
+			return
+		}
+		dict := zerolog.Dict()
+		sub := append(groups[:len(groups):len(groups)], a.Key)
+		for _, ga := range attrs {
+			h.appendAttr(dict, sub, ga)
+		}
+		e.Dict(a.Key, dict)
+	default:
+		switch v := a.Value.Any().(type) {
+		case error:
+			e.AnErr(a.Key, v)
+		case zerolog.LogObjectMarshaler:
+			e.Object(a.Key, v)
+		default:
+			e.Interface(a.Key, v)
+		}
+	}
+}
+
+func slogLevel(level slog.Level) zerolog.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return zerolog.TraceLevel
+	case level < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case level < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+func (l *Logger) slogEvent(ctx context.Context, level slog.Level) *zerolog.Event {
+	switch {
+	case level < slog.LevelDebug:
+		return l.Default(ctx)
+	case level < slog.LevelInfo:
+		return l.Debug(ctx)
+	case level < LevelNotice:
+		return l.Info(ctx)
+	case level < slog.LevelWarn:
+		return l.Notice(ctx)
+	case level < slog.LevelError:
+		return l.Warn(ctx)
+	}
+	// the caller is taken from the record, not from the handler
+	logger := &l.backend().errOut
+	switch {
+	case level < LevelCritical:
+		return l.newEvent(ctx, logger, zerolog.ErrorLevel, "ERROR")
+	case level < LevelAlert:
+		return l.newEvent(ctx, logger, zerolog.ErrorLevel, "CRITICAL")
+	case level < LevelEmergency:
+		return l.newEvent(ctx, logger, zerolog.ErrorLevel, "ALERT")
+	default:
+		return l.newEvent(ctx, logger, zerolog.ErrorLevel, "EMERGENCY")
+	}
+}
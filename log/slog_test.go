@@ -0,0 +1,74 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSlogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetGlobalOut(buf)
+	SetGlobalErr(buf)
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		if strings.HasSuffix(t.Name(), "/zero-time") {
+			t.Skip("timestamp is always added by the logger")
+		}
+		buf.Reset()
+		return NewSlogHandler(nil)
+	}, func(t *testing.T) map[string]any {
+		m := make(map[string]any)
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		m[slog.TimeKey] = m[zerolog.TimestampFieldName]
+		m[slog.MessageKey] = m[zerolog.MessageFieldName]
+		return m
+	})
+}
+
+func TestSlogHandler_Severity(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{level: slog.LevelDebug, want: "DEBUG"},
+		{level: slog.LevelInfo, want: "INFO"},
+		{level: LevelNotice, want: "NOTICE"},
+		{level: slog.LevelWarn, want: "WARNING"},
+		{level: slog.LevelError, want: "ERROR"},
+		{level: LevelCritical, want: "CRITICAL"},
+		{level: LevelAlert, want: "ALERT"},
+		{level: LevelEmergency, want: "EMERGENCY"},
+	}
+	buf := &bytes.Buffer{}
+	SetGlobalOut(buf)
+	SetGlobalErr(buf)
+	logger := slog.New(NewSlogHandler(nil))
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			buf.Reset()
+			ctx := WithObject(context.Background(), Object{"user": "test"})
+			logger.Log(ctx, tt.level, "message")
+			m := make(map[string]any)
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+			if m["severity"] != tt.want {
+				t.Errorf("severity = %v, want %v", m["severity"], tt.want)
+			}
+			if m["user"] != "test" {
+				t.Errorf("user = %v, want %v", m["user"], "test")
+			}
+			if _, ok := m[zerolog.CallerFieldName]; ok != (tt.level >= slog.LevelError) {
+				t.Errorf("caller = %v", m[zerolog.CallerFieldName])
+			}
+		})
+	}
+}
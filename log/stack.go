@@ -0,0 +1,142 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrorFormatter adds err, the chain of err and the stack trace to e.
+type ErrorFormatter func(e *zerolog.Event, err error, chain ErrorChain, stack string) *zerolog.Event
+
+// ErrorReportingFormatter writes the stack trace to stack_trace, which Google Cloud Error Reporting recognises.
+func ErrorReportingFormatter(e *zerolog.Event, err error, chain ErrorChain, stack string) *zerolog.Event {
+	return e.Str(zerolog.ErrorFieldName, err.Error()).Array("errors", chain).Str("stack_trace", stack)
+}
+
+var errorFormatter atomic.Pointer[ErrorFormatter]
+
+var stackDepth atomic.Int32
+
+func init() {
+	SetErrorFormatter(ErrorReportingFormatter)
+	SetStackDepth(32)
+}
+
+// SetErrorFormatter changes how ErrorWithStack and Err write errors.
+func SetErrorFormatter(f ErrorFormatter) {
+	errorFormatter.Store(&f)
+}
+
+// SetStackDepth sets the maximum number of frames of the captured stack traces.
+func SetStackDepth(depth int) {
+	stackDepth.Store(int32(depth))
+}
+
+// ErrorWithStack returns an ERROR event with err, the chain of err and the stack trace of the caller.
+func ErrorWithStack(ctx context.Context, err error, skip ...int) *zerolog.Event {
+	return DefaultLogger().errorWithStack(ctx, err, skip...)
+}
+
+// ErrorWithStack returns an ERROR event with err, the chain of err and the stack trace of the caller.
+func (l *Logger) ErrorWithStack(ctx context.Context, err error, skip ...int) *zerolog.Event {
+	return l.errorWithStack(ctx, err, skip...)
+}
+
+func (l *Logger) errorWithStack(ctx context.Context, err error, skip ...int) *zerolog.Event {
+	e := l.Error(ctx, skip...)
+	if e == nil || err == nil {
+		return e
+	}
+	return withError(e, err, 2+skipCount(skip))
+}
+
+// Err adds err, the chain of err and the stack trace of the caller to e.
+func Err(e *zerolog.Event, err error, skip ...int) *zerolog.Event {
+	if e == nil || err == nil {
+		return e
+	}
+	return withError(e, err, 1+skipCount(skip))
+}
+
+// MarshalStack is a zerolog.ErrorStackMarshaler that captures the stack trace of the caller of Event.Err.
+// It is enabled with:
+//
+//	zerolog.ErrorStackMarshaler = log.MarshalStack
+func MarshalStack(err error) interface{} {
+	return Stack(2)
+}
+
+func skipCount(skip []int) int {
+	if len(skip) > 0 {
+		return skip[0]
+	}
+	return 0
+}
+
+func withError(e *zerolog.Event, err error, skip int) *zerolog.Event {
+	f := *errorFormatter.Load()
+	return f(e, err, Chain(err), Stack(skip+1))
+}
+
+// ErrorChain is the list of errors obtained by errors.Unwrap and errors.Join.
+type ErrorChain []error
+
+// Chain returns err and all the errors wrapped by err, depth first.
+func Chain(err error) ErrorChain {
+	chain := make(ErrorChain, 0, 4)
+	var walk func(err error)
+	walk = func(err error) {
+		for err != nil {
+			chain = append(chain, err)
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range joined.Unwrap() {
+					walk(e)
+				}
+				return
+			}
+			err = errors.Unwrap(err)
+		}
+	}
+	walk(err)
+	return chain
+}
+
+func (chain ErrorChain) MarshalZerologArray(a *zerolog.Array) {
+	for _, err := range chain {
+		a.Dict(zerolog.Dict().Str("message", err.Error()).Str("type", fmt.Sprintf("%T", err)))
+	}
+}
+
+// Stack returns the stack trace of the current goroutine in the format of runtime.Stack,
+// skipping the given number of frames (0 is the caller of Stack).
+func Stack(skip int) string {
+	pcs := make([]uintptr, stackDepth.Load())
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var sb strings.Builder
+	sb.WriteString(goroutineHeader())
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "%s(...)\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+func goroutineHeader() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		return string(buf[:i+1])
+	}
+	return "goroutine 1 [running]:\n"
+}
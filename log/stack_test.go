@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorWithStack(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(WithErr(buf))
+	base := errors.New("base")
+	err := fmt.Errorf("wrapped: %w", errors.Join(base, errors.New("other")))
+	logger.ErrorWithStack(context.Background(), err).Msg("failed")
+
+	var m struct {
+		Error  string `json:"error"`
+		Errors []struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"errors"`
+		Stack string `json:"stack_trace"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Error != err.Error() {
+		t.Errorf("error = %s, want %s", m.Error, err.Error())
+	}
+	if len(m.Errors) != 4 || m.Errors[2].Message != "base" {
+		t.Errorf("errors = %v", m.Errors)
+	}
+	lines := strings.Split(m.Stack, "\n")
+	if !strings.HasPrefix(lines[0], "goroutine ") || !strings.HasSuffix(lines[1], "log.TestErrorWithStack(...)") {
+		t.Errorf("stack = %s", m.Stack)
+	}
+}
@@ -80,6 +80,7 @@ func (b *Processor) Form(ctx context.Context, v any) (data []byte, err error) {
 	case string:
 		str = val
 	default:
+		form = url.Values{}
 		if err = encoder.Encode(v, form); err != nil {
 			return nil, err
 		}
@@ -100,21 +101,22 @@ func (b *Processor) Form(ctx context.Context, v any) (data []byte, err error) {
 	return []byte(maskingForm(ctx, form, b.keys).Encode()), nil
 }
 
+// Values returns a copy of form in which the values of the keys are masked.
+func (b *Processor) Values(ctx context.Context, form url.Values) url.Values {
+	body := make(url.Values, len(form))
+	for k, v := range form {
+		body[k] = append([]string(nil), v...)
+	}
+	return maskingForm(ctx, body, b.keys)
+}
+
 // maskingForm processes a form-encoded body and masks values based on the keys provided.
 func maskingForm(ctx context.Context, body url.Values, keys []string) url.Values {
 	for k, v := range body {
-		for _, s := range keys {
-			if _, ok := body[s]; ok { // Exact match
-				for i, val := range v {
-					if val != "" {
-						body[k][i] = MaskValue
-					}
-				}
-			} else if strings.EqualFold(k, s) { // Case-insensitive match
-				for i, val := range v {
-					if val != "" {
-						body[k][i] = MaskValue
-					}
+		if contains(keys, k) {
+			for i, val := range v {
+				if val != "" {
+					v[i] = MaskValue
 				}
 			}
 		}
@@ -140,9 +140,18 @@ func TestForm(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(form["password"]))
 	assert.Equal(t, MaskValue, form["password"][0])
+	assert.Equal(t, "test_user", form.Get("username"))
 
 	str = ""
 	data, err = New("password").Form(ctx, str)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(data))
 }
+
+func TestProcessor_Values(t *testing.T) {
+	form := url.Values{"username": {"test_user"}, "Password": {"qwerty", ""}}
+	masked := New("password").Values(context.Background(), form)
+	assert.Equal(t, []string{MaskValue, ""}, masked["Password"])
+	assert.Equal(t, "test_user", masked.Get("username"))
+	assert.Equal(t, "qwerty", form["Password"][0])
+}
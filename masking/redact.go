@@ -0,0 +1,172 @@
+package masking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RedactHeaders are the headers whose values are redacted by default.
+var RedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// DefaultMaxBodySize is the size above which bodies are truncated by default.
+const DefaultMaxBodySize = 64 * 1024
+
+// Redactor redacts the headers and the bodies of HTTP messages written to logs.
+type Redactor struct {
+	processor   *Processor
+	headers     map[string]bool
+	reveal      int
+	maxBodySize int
+}
+
+// NewRedactor creates a Redactor of RedactHeaders truncating bodies above DefaultMaxBodySize.
+func NewRedactor() *Redactor {
+	r := &Redactor{
+		headers:     make(map[string]bool, len(RedactHeaders)),
+		maxBodySize: DefaultMaxBodySize,
+	}
+	return r.AddHeaders(RedactHeaders...)
+}
+
+// SetProcessor masks the values of JSON and form bodies with p.
+func (r *Redactor) SetProcessor(p *Processor) *Redactor {
+	r.processor = p
+	return r
+}
+
+// AddHeaders adds headers to redact.
+func (r *Redactor) AddHeaders(names ...string) *Redactor {
+	for _, name := range names {
+		r.headers[http.CanonicalHeaderKey(name)] = true
+	}
+	return r
+}
+
+// Reveal shows the first n characters of the redacted header values. The default is 0.
+// The authentication scheme of Authorization, such as Bearer, and the cookie names are always shown.
+func (r *Redactor) Reveal(n int) *Redactor {
+	r.reveal = n
+	return r
+}
+
+// SetMaxBodySize sets the size above which bodies are truncated.
+// A non-positive size disables truncation.
+func (r *Redactor) SetMaxBodySize(size int) *Redactor {
+	r.maxBodySize = size
+	return r
+}
+
+// MaxBodySize returns the size above which bodies are truncated.
+func (r *Redactor) MaxBodySize() int {
+	return r.maxBodySize
+}
+
+// Header returns the values of the header, redacted if it is one of the headers to redact.
+func (r *Redactor) Header(name string, values []string) []string {
+	name = http.CanonicalHeaderKey(name)
+	if !r.headers[name] {
+		return values
+	}
+	redacted := make([]string, 0, len(values))
+	for _, v := range values {
+		redacted = append(redacted, r.redactValue(name, v))
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(name, value string) string {
+	switch name {
+	case "Authorization", "Proxy-Authorization":
+		if scheme, credentials, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + r.mask(credentials)
+		}
+	case "Cookie":
+		cookies := strings.Split(value, ";")
+		for i, cookie := range cookies {
+			cookies[i] = r.maskCookie(cookie)
+		}
+		return strings.Join(cookies, ";")
+	case "Set-Cookie":
+		cookie, _, _ := strings.Cut(value, ";")
+		return r.maskCookie(cookie)
+	}
+	return r.mask(value)
+}
+
+func (r *Redactor) maskCookie(cookie string) string {
+	if name, value, ok := strings.Cut(cookie, "="); ok {
+		return name + "=" + r.mask(value)
+	}
+	return r.mask(cookie)
+}
+
+func (r *Redactor) mask(value string) string {
+	if r.reveal > 0 && len(value) > r.reveal {
+		return value[:r.reveal] + MaskValue
+	}
+	return MaskValue
+}
+
+// Body returns the body of the content type masked and truncated.
+// head is the whole body when more is 0. Otherwise it is the head of the body
+// and more is the number of the remaining bytes, negative if it is unknown.
+// A truncated body cannot be parsed, so it is replaced with MaskValue when a Processor is set.
+func (r *Redactor) Body(ctx context.Context, contentType string, head []byte, more int64) string {
+	if more != 0 {
+		body := string(head)
+		if r.processor != nil {
+			body = MaskValue
+		}
+		return truncated(body, more)
+	}
+	body := r.maskBody(ctx, contentType, string(head))
+	if r.maxBodySize > 0 && len(body) > r.maxBodySize {
+		return truncated(body[:r.maxBodySize], int64(len(body)-r.maxBodySize))
+	}
+	return body
+}
+
+func (r *Redactor) maskBody(ctx context.Context, contentType, body string) string {
+	if r.processor == nil || body == "" {
+		return body
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		if form, err := url.ParseQuery(body); err == nil {
+			return r.processor.Values(ctx, form).Encode()
+		}
+		return MaskValue
+	case strings.HasSuffix(mediaType, "json") || strings.HasPrefix(body, "{") || strings.HasPrefix(body, "["):
+		if data, err := r.processor.Json(ctx, body); err == nil {
+			return string(data)
+		}
+		return MaskValue
+	}
+	return body
+}
+
+func truncated(body string, more int64) string {
+	if more > 0 {
+		return fmt.Sprintf("%s...(truncated %d bytes)", body, more)
+	}
+	return body + "...(truncated)"
+}
+
+// CompactJSON returns body compacted if it is a JSON object or array.
+func CompactJSON(body string) ([]byte, bool) {
+	if !strings.HasPrefix(body, "{") && !strings.HasPrefix(body, "[") {
+		return nil, false
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(body)); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
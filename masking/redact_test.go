@@ -0,0 +1,87 @@
+package masking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Header(t *testing.T) {
+	r := NewRedactor().AddHeaders("X-Secret")
+	assert.Equal(t, []string{"Bearer " + MaskValue}, r.Header("authorization", []string{"Bearer abcdefgh"}))
+	assert.Equal(t, []string{"a=" + MaskValue + "; b=" + MaskValue}, r.Header("Cookie", []string{"a=1234; b=5678"}))
+	assert.Equal(t, []string{MaskValue}, r.Header("X-Secret", []string{"secret"}))
+	assert.Equal(t, []string{"text/html"}, r.Header("Accept", []string{"text/html"}))
+
+	r.Reveal(3)
+	assert.Equal(t, []string{"session=sec" + MaskValue}, r.Header("Set-Cookie", []string{"session=secret; Path=/"}))
+}
+
+func TestRedactor_Body(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		redactor    *Redactor
+		contentType string
+		body        string
+		more        int64
+		want        string
+	}{
+		{
+			name:        "json",
+			redactor:    NewRedactor().SetProcessor(New("password")),
+			contentType: "application/json",
+			body:        `{"user":"test","password":"qwerty"}`,
+			want:        `{"password":"*****","user":"test"}`,
+		},
+		{
+			name:        "form",
+			redactor:    NewRedactor().SetProcessor(New("password")),
+			contentType: "application/x-www-form-urlencoded",
+			body:        "user=test&password=qwerty",
+			want:        "password=%2A%2A%2A%2A%2A&user=test",
+		},
+		{
+			name:        "invalid json",
+			redactor:    NewRedactor().SetProcessor(New("password")),
+			contentType: "application/json",
+			body:        `{"password":`,
+			want:        MaskValue,
+		},
+		{
+			name:     "truncate",
+			redactor: NewRedactor().SetMaxBodySize(4),
+			body:     "0123456789",
+			want:     "0123...(truncated 6 bytes)",
+		},
+		{
+			name:     "head",
+			redactor: NewRedactor(),
+			body:     "0123",
+			more:     6,
+			want:     "0123...(truncated 6 bytes)",
+		},
+		{
+			name:        "masked head",
+			redactor:    NewRedactor().SetProcessor(New("password")),
+			contentType: "application/json",
+			body:        `{"pass`,
+			more:        -1,
+			want:        MaskValue + "...(truncated)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.redactor.Body(ctx, tt.contentType, []byte(tt.body), tt.more))
+		})
+	}
+}
+
+func TestCompactJSON(t *testing.T) {
+	data, ok := CompactJSON("{\n  \"a\": 1\n}")
+	assert.True(t, ok)
+	assert.Equal(t, `{"a":1}`, string(data))
+	_, ok = CompactJSON("text")
+	assert.False(t, ok)
+}
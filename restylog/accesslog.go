@@ -7,7 +7,6 @@ import (
 
 	"github.com/go-resty/resty/v2"
 	"github.com/goccha/http-constants/pkg/headers"
-	"github.com/goccha/logging/log"
 	"github.com/rs/zerolog"
 )
 
@@ -21,10 +20,10 @@ func WriteLog(ctx context.Context, name string, req *resty.Request, res *resty.R
 	if res != nil {
 		status = res.StatusCode()
 		latency = res.Time()
-		ev = log.Info(ctx)
+		ev = logger.Info(ctx)
 	} else {
 		latency = time.Since(req.Time)
-		ev = log.Notice(ctx)
+		ev = logger.Notice(ctx)
 	}
 	ev.Str("client", name).Dict("httpClient", zerolog.Dict().
 		Int("status", status).Str("userAgent", ua).
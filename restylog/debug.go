@@ -0,0 +1,145 @@
+package restylog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/goccha/logging/masking"
+	"github.com/rs/zerolog"
+)
+
+type DebugOption func(d *debugLogger)
+
+// WithMasking masks the values of JSON and form bodies with p.
+func WithMasking(p *masking.Processor) DebugOption {
+	return func(d *debugLogger) {
+		d.redactor.SetProcessor(p)
+	}
+}
+
+// WithMaskKeys masks the values of the keys in JSON and form bodies.
+func WithMaskKeys(keys ...string) DebugOption {
+	return func(d *debugLogger) {
+		d.redactor.SetProcessor(masking.New(keys...))
+	}
+}
+
+// WithRedactHeaders adds headers to masking.RedactHeaders.
+func WithRedactHeaders(headers ...string) DebugOption {
+	return func(d *debugLogger) {
+		d.redactor.AddHeaders(headers...)
+	}
+}
+
+// WithMaxBodySize sets the size above which bodies are truncated. The default is 64KiB.
+// A non-positive size disables truncation.
+func WithMaxBodySize(size int) DebugOption {
+	return func(d *debugLogger) {
+		d.redactor.SetMaxBodySize(size)
+	}
+}
+
+type debugLogger struct {
+	redactor *masking.Redactor
+}
+
+func newDebugLogger(opts ...DebugOption) *debugLogger {
+	d := &debugLogger{
+		redactor: masking.NewRedactor(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// logResponse logs the request and the response of an attempt with the context of the request.
+func (d *debugLogger) logResponse(_ *resty.Client, res *resty.Response) error {
+	r := res.Request
+	d.write(r.Context(), r, res, nil)
+	return nil
+}
+
+// logError logs the request that failed without a response.
+func (d *debugLogger) logError(r *resty.Request, err error) {
+	if re, ok := err.(*resty.ResponseError); ok {
+		// the response has already been logged by logResponse
+		if re.Response != nil && re.Response.RawResponse != nil {
+			return
+		}
+		err = re.Err
+	}
+	d.write(r.Context(), r, nil, err)
+}
+
+func (d *debugLogger) write(ctx context.Context, r *resty.Request, res *resty.Response, err error) {
+	e := logger.Debug(ctx)
+	if e == nil {
+		return
+	}
+	url, header := r.URL, r.Header
+	if r.RawRequest != nil {
+		url, header = r.RawRequest.URL.String(), r.RawRequest.Header
+	}
+	req := zerolog.Dict().Str("method", r.Method).Str("url", url).
+		Dict("headers", d.headers(header))
+	d.appendBody(ctx, req, header.Get("Content-Type"), requestBody(r))
+	e = e.Str("client", "resty").Int("attempt", r.Attempt).Dict("request", req)
+	if res != nil && res.RawResponse != nil {
+		body := zerolog.Dict().Dict("headers", d.headers(res.Header()))
+		d.appendBody(ctx, body, res.Header().Get("Content-Type"), string(res.Body()))
+		e = e.Int("status", res.StatusCode()).Dict("response", body).
+			Str("latency", fmt.Sprintf("%vs", res.Time().Seconds()))
+	} else {
+		e = e.Str("latency", fmt.Sprintf("%vs", time.Since(r.Time).Seconds()))
+	}
+	if err != nil {
+		e = e.Err(err)
+	}
+	e.Send()
+}
+
+func (d *debugLogger) headers(header http.Header) *zerolog.Event {
+	headers := zerolog.Dict()
+	for k, v := range header {
+		headers.Strs(k, d.redactor.Header(k, v))
+	}
+	return headers
+}
+
+func (d *debugLogger) appendBody(ctx context.Context, dict *zerolog.Event, contentType, body string) {
+	body = d.redactor.Body(ctx, contentType, []byte(body), 0)
+	if data, ok := masking.CompactJSON(body); ok {
+		dict.RawJSON("body", data)
+	} else {
+		dict.Str("body", body)
+	}
+}
+
+// requestBody returns the body of r as it is sent.
+func requestBody(r *resty.Request) string {
+	switch b := r.Body.(type) {
+	case nil:
+		if len(r.FormData) > 0 {
+			return r.FormData.Encode()
+		}
+		return ""
+	case string:
+		return b
+	case []byte:
+		return string(b)
+	case io.Reader:
+		return "***** STREAM *****"
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Sprintf("%v", b)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,140 @@
+package restylog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/goccha/logging/log"
+	"github.com/goccha/logging/tracing"
+	"github.com/goccha/logging/tracing/tracelog"
+)
+
+func TestSetDebug(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("resty"))
+	SetFormat(Json)
+	defer SetFormat(Default)
+	tracelog.Setup()
+
+	c := SetDebug(resty.New(), true)
+	ctx := tracing.WithContext(context.Background(), &tracelog.TracingContext{RequestID: "request-id"})
+	if _, err := c.R().SetContext(ctx).SetBody(map[string]string{"name": "test"}).Post(srv.URL + "/users"); err != nil {
+		t.Fatal(err)
+	}
+
+	var m struct {
+		RequestId string `json:"request_id"`
+		Attempt   int    `json:"attempt"`
+		Status    int    `json:"status"`
+		Latency   string `json:"latency"`
+		Request   struct {
+			Method string          `json:"method"`
+			Url    string          `json:"url"`
+			Body   json.RawMessage `json:"body"`
+		} `json:"request"`
+		Response struct {
+			Body json.RawMessage `json:"body"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if m.RequestId != "request-id" || m.Attempt != 1 || m.Status != http.StatusOK || m.Latency == "" ||
+		m.Request.Method != http.MethodPost || m.Request.Url != srv.URL+"/users" ||
+		string(m.Request.Body) != `{"name":"test"}` || string(m.Response.Body) != `{"id":1}` {
+		t.Errorf("log = %s", buf.String())
+	}
+}
+
+func TestSetDebug_Masking(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"secret","description":"0123456789"}`))
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	SetLogger(log.New(log.WithOut(buf)))
+	defer SetLogger(log.Named("resty"))
+	SetFormat(Json)
+	defer SetFormat(Default)
+
+	tests := []struct {
+		name     string
+		opts     []DebugOption
+		request  func(r *resty.Request) *resty.Request
+		wantReq  string
+		wantResp string
+	}{
+		{
+			name: "json",
+			opts: []DebugOption{WithMaskKeys("password", "token")},
+			request: func(r *resty.Request) *resty.Request {
+				return r.SetBody(map[string]string{"user": "test", "password": "qwerty"})
+			},
+			wantReq:  `{"password":"*****","user":"test"}`,
+			wantResp: `{"description":"0123456789","token":"*****"}`,
+		},
+		{
+			name: "form",
+			opts: []DebugOption{WithMaskKeys("password", "token")},
+			request: func(r *resty.Request) *resty.Request {
+				return r.SetFormData(map[string]string{"user": "test", "password": "qwerty"})
+			},
+			wantReq:  `"password=%2A%2A%2A%2A%2A&user=test"`,
+			wantResp: `{"description":"0123456789","token":"*****"}`,
+		},
+		{
+			name: "truncate",
+			opts: []DebugOption{WithMaxBodySize(10)},
+			request: func(r *resty.Request) *resty.Request {
+				return r.SetBody("0123456789abc")
+			},
+			wantReq:  `"0123456789...(truncated 3 bytes)"`,
+			wantResp: `"{\"token\":\"...(truncated 35 bytes)"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			c := SetDebug(resty.New(), true, tt.opts...)
+			r := c.R().SetHeader("Authorization", "Bearer secret")
+			if _, err := tt.request(r).Post(srv.URL); err != nil {
+				t.Fatal(err)
+			}
+			var m struct {
+				Request struct {
+					Headers map[string][]string `json:"headers"`
+					Body    json.RawMessage     `json:"body"`
+				} `json:"request"`
+				Response struct {
+					Headers map[string][]string `json:"headers"`
+					Body    json.RawMessage     `json:"body"`
+				} `json:"response"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatal(err, buf.String())
+			}
+			if auth, cookie := m.Request.Headers["Authorization"], m.Response.Headers["Set-Cookie"]; len(auth) != 1 || auth[0] != "Bearer *****" ||
+				len(cookie) != 1 || cookie[0] != "session=*****" {
+				t.Errorf("headers are not redacted: %s", buf.String())
+			}
+			if string(m.Request.Body) != tt.wantReq || string(m.Response.Body) != tt.wantResp {
+				t.Errorf("request = %s, response = %s", m.Request.Body, m.Response.Body)
+			}
+		})
+	}
+}
@@ -16,6 +16,13 @@ const (
 
 var _logger = &Logger{}
 
+var logger = log.Named("resty")
+
+// SetLogger changes the logger used by restylog. The default is log.Named("resty").
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
 func SetFormat(format string) {
 	switch format {
 	case Json:
@@ -27,19 +34,26 @@ func SetFormat(format string) {
 
 var debugFormat = Default
 
-func SetDebug(c *resty.Client, debug bool) *resty.Client {
+// SetDebug enables the debug dumps of requests and responses.
+// With the Json format, headers in masking.RedactHeaders are redacted, bodies are masked with the options
+// and truncated above the maximum size. The options are ignored with the Default format.
+func SetDebug(c *resty.Client, debug bool, opts ...DebugOption) *resty.Client {
 	if debug {
 		if debugFormat == Json {
-			return c.OnRequestLog(RequestLogCallback).
-				OnResponseLog(ResponseLogCallback).
-				SetLogger(_logger).
-				SetDebug(debug)
+			// The dumps are written by middlewares instead of the log callbacks,
+			// so that they are logged with the context of the request.
+			d := newDebugLogger(opts...)
+			return c.OnAfterResponse(d.logResponse).
+				OnError(d.logError).
+				SetLogger(_logger)
 		}
 		return c.SetDebug(debug)
 	}
 	return c
 }
 
+// Deprecated: RequestLogCallback logs without the context of the request.
+// SetDebug with the Json format logs the request and the response together with the context.
 func RequestLogCallback(req *resty.RequestLog) error {
 	body := zerolog.Dict()
 	headers := zerolog.Dict()
@@ -52,10 +66,12 @@ func RequestLogCallback(req *resty.RequestLog) error {
 	} else {
 		body.Str("body", req.Body)
 	}
-	log.Debug(context.TODO()).Str("client", "resty").Dict("request", body).Send()
+	logger.Debug(context.TODO()).Str("client", "resty").Dict("request", body).Send()
 	return nil
 }
 
+// Deprecated: ResponseLogCallback logs without the context of the request.
+// SetDebug with the Json format logs the request and the response together with the context.
 func ResponseLogCallback(res *resty.ResponseLog) error {
 	body := zerolog.Dict()
 	headers := zerolog.Dict()
@@ -68,17 +84,17 @@ func ResponseLogCallback(res *resty.ResponseLog) error {
 	} else {
 		body.Str("body", res.Body)
 	}
-	log.Debug(context.TODO()).Str("client", "resty").Dict("response", body).Send()
+	logger.Debug(context.TODO()).Str("client", "resty").Dict("response", body).Send()
 	return nil
 }
 
 type Logger struct{}
 
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	log.Error(context.TODO()).Msgf("RESTY "+format, v...)
+	logger.Error(context.TODO()).Msgf("RESTY "+format, v...)
 }
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	log.Warn(context.TODO()).Msgf("RESTY "+format, v...)
+	logger.Warn(context.TODO()).Msgf("RESTY "+format, v...)
 }
 func (l *Logger) Debugf(format string, v ...interface{}) {
 	if len(v) > 0 {
@@ -88,5 +104,5 @@ func (l *Logger) Debugf(format string, v ...interface{}) {
 			}
 		}
 	}
-	log.Debug(context.TODO()).Msgf("RESTY "+format, v...)
+	logger.Debug(context.TODO()).Msgf("RESTY "+format, v...)
 }
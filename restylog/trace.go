@@ -0,0 +1,181 @@
+package restylog
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/goccha/logging/tracing/tracelog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the client spans.
+const ScopeName = "github.com/goccha/logging/restylog"
+
+type TraceOption func(o *traceOption)
+
+// WithTracerProvider sets the provider of the client spans. The default is the global provider.
+func WithTracerProvider(tp trace.TracerProvider) TraceOption {
+	return func(o *traceOption) {
+		o.provider = tp
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into the request headers.
+// The default is the global propagator.
+func WithPropagator(p propagation.TextMapPropagator) TraceOption {
+	return func(o *traceOption) {
+		o.propagator = p
+	}
+}
+
+type traceOption struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// SetTracing propagates the request ID and the trace context of the request context to downstream services
+// and records each attempt of a request as a client span.
+func SetTracing(c *resty.Client, opts ...TraceOption) *resty.Client {
+	o := &traceOption{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.provider == nil {
+		o.provider = otel.GetTracerProvider()
+	}
+	if o.propagator == nil {
+		o.propagator = otel.GetTextMapPropagator()
+	}
+	t := &tracer{
+		tracer:     o.provider.Tracer(ScopeName),
+		propagator: o.propagator,
+	}
+	return c.OnBeforeRequest(PropagateRequestId).
+		OnBeforeRequest(t.startSpan).
+		OnAfterResponse(t.endSpan).
+		OnError(t.recordError)
+}
+
+// PropagateRequestId sets the request ID of the tracing context in the request context
+// to the header named by tracelog.Config.RequestIdHeaderName, unless the request already has it.
+func PropagateRequestId(_ *resty.Client, r *resty.Request) error {
+	name := tracelog.TraceConfig().RequestIdHeaderName()
+	if r.Header.Get(name) != "" {
+		return nil
+	}
+	if id := tracelog.RequestId(r.Context()); id != "" {
+		r.SetHeader(name, id)
+	}
+	return nil
+}
+
+type parentKey struct{}
+
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func (t *tracer) startSpan(c *resty.Client, r *resty.Request) error {
+	ctx := r.Context()
+	parent, ok := ctx.Value(parentKey{}).(context.Context)
+	if ok {
+		// retry: the previous attempt failed without a response
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.SetStatus(codes.Error, "retry")
+			span.End()
+		}
+	} else {
+		parent = ctx
+	}
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(r.Method)}
+	if u := requestURL(c, r); u != nil {
+		attrs = append(attrs, urlAttributes(u)...)
+	}
+	ctx, _ = t.tracer.Start(parent, r.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	if r.Attempt > 1 {
+		trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPRequestResendCount(r.Attempt - 1))
+	}
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	r.SetContext(context.WithValue(ctx, parentKey{}, parent))
+	return nil
+}
+
+func (t *tracer) endSpan(_ *resty.Client, res *resty.Response) error {
+	span := trace.SpanFromContext(res.Request.Context())
+	if !span.IsRecording() {
+		return nil
+	}
+	if raw := res.Request.RawRequest; raw != nil {
+		span.SetAttributes(urlAttributes(raw.URL)...)
+	}
+	status := res.StatusCode()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= 400 {
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(status)))
+		span.SetStatus(codes.Error, "")
+	}
+	span.End()
+	return nil
+}
+
+func (t *tracer) recordError(r *resty.Request, err error) {
+	span := trace.SpanFromContext(r.Context())
+	if !span.IsRecording() {
+		return
+	}
+	if raw := r.RawRequest; raw != nil {
+		span.SetAttributes(urlAttributes(raw.URL)...)
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+}
+
+// requestURL returns the URL of the request joined with the base URL of the client as resty does.
+// The path parameters and the query parameters are not applied yet when the span starts,
+// so the URL is replaced with the one of the sent request when the span ends.
+func requestURL(c *resty.Client, r *resty.Request) *url.URL {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil
+	}
+	if !u.IsAbs() {
+		path := u.String()
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if u, err = url.Parse(c.BaseURL + path); err != nil || u.Host == "" {
+			return nil
+		}
+	}
+	return u
+}
+
+func urlAttributes(u *url.URL) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.URLFull(u.String()), semconv.ServerAddress(u.Hostname())}
+	if port, err := strconv.Atoi(u.Port()); err == nil {
+		attrs = append(attrs, semconv.ServerPort(port))
+	} else if port = defaultPort(u.Scheme); port > 0 {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	return attrs
+}
+
+func defaultPort(scheme string) int {
+	switch scheme {
+	case "http":
+		return 80
+	case "https":
+		return 443
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package restylog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/goccha/logging/tracing"
+	"github.com/goccha/logging/tracing/tracelog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetTracing(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := SetTracing(resty.New().SetBaseURL(srv.URL), WithTracerProvider(tp), WithPropagator(propagation.TraceContext{}))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	ctx = tracing.WithContext(ctx, &tracelog.TracingContext{RequestID: "request-id"})
+	if _, err := c.R().SetContext(ctx).Get("/users"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.R().SetContext(ctx).Get("/missing"); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	if got := header.Get("X-Request-ID"); got != "request-id" {
+		t.Errorf("X-Request-ID = %s", got)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %d", len(spans))
+	}
+	if got, want := header.Get("Traceparent"), "00-"+spans[1].SpanContext().TraceID().String()+"-"+spans[1].SpanContext().SpanID().String()+"-01"; got != want {
+		t.Errorf("traceparent = %s, want %s", got, want)
+	}
+	for i, span := range spans[:2] {
+		if span.SpanKind() != trace.SpanKindClient || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d: kind = %v, parent = %v", i, span.SpanKind(), span.Parent())
+		}
+	}
+	attrs := make(map[string]any)
+	for _, kv := range spans[1].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs[string(semconv.HTTPResponseStatusCodeKey)] != int64(http.StatusNotFound) ||
+		attrs[string(semconv.URLFullKey)] != srv.URL+"/missing" || spans[1].Status().Code != codes.Error {
+		t.Errorf("attributes = %v, status = %v", attrs, spans[1].Status())
+	}
+}
+
+type attributeSampler struct {
+	attrs []attribute.KeyValue
+}
+
+func (s *attributeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.attrs = p.Attributes
+	return sdktrace.AlwaysSample().ShouldSample(p)
+}
+
+func (s *attributeSampler) Description() string {
+	return "attributes"
+}
+
+func TestSetTracing_Error(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	sampler := &attributeSampler{}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+	c := SetTracing(resty.New().SetBaseURL("http://127.0.0.1:1"), WithTracerProvider(tp))
+	if _, err := c.R().Get("/users"); err == nil {
+		t.Fatal("no error")
+	}
+
+	want := attribute.NewSet(semconv.URLFull("http://127.0.0.1:1/users"), semconv.ServerAddress("127.0.0.1"), semconv.ServerPort(1))
+	for name, attrs := range map[string][]attribute.KeyValue{"sampler": sampler.attrs, "span": recorder.Ended()[0].Attributes()} {
+		set := attribute.NewSet(attrs...)
+		for _, kv := range want.ToSlice() {
+			if v, ok := set.Value(kv.Key); !ok || v != kv.Value {
+				t.Errorf("%s: %s = %v, want %v", name, kv.Key, v.Emit(), kv.Value.Emit())
+			}
+		}
+	}
+}
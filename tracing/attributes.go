@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/goccha/http-constants/pkg/headers"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// RequestAttributes returns the span attributes of an HTTP server request
+// of the OpenTelemetry semantic conventions. An empty route is not added.
+func RequestAttributes(req *http.Request, route string) []attribute.KeyValue {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLScheme(scheme),
+		semconv.URLPath(req.URL.Path),
+	}
+	if route != "" {
+		attrs = append(attrs, semconv.HTTPRoute(route))
+	}
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, semconv.URLQuery(req.URL.RawQuery))
+	}
+	if host, port, err := net.SplitHostPort(req.Host); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(p))
+		}
+	} else if req.Host != "" {
+		attrs = append(attrs, semconv.ServerAddress(req.Host))
+	}
+	if l := req.ContentLength; l > 0 {
+		attrs = append(attrs, semconv.HTTPRequestBodySize(int(l)))
+	}
+	if ip := ClientIP(req); ip != "" {
+		attrs = append(attrs, semconv.ClientAddress(ip))
+	}
+	if ua := req.Header.Get(headers.UserAgent); ua != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(ua))
+	}
+	return attrs
+}
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestRequestAttributes(t *testing.T) {
+	req := httptest.NewRequest("POST", "https://example.com:8443/items/1?q=a", strings.NewReader("body"))
+	req.Header.Set("User-Agent", "test")
+	got := attribute.NewSet(RequestAttributes(req, "/items/:id")...)
+	want := map[attribute.Key]attribute.Value{
+		semconv.HTTPRequestMethodKey:   attribute.StringValue("POST"),
+		semconv.URLSchemeKey:           attribute.StringValue("https"),
+		semconv.URLPathKey:             attribute.StringValue("/items/1"),
+		semconv.HTTPRouteKey:           attribute.StringValue("/items/:id"),
+		semconv.URLQueryKey:            attribute.StringValue("q=a"),
+		semconv.ServerAddressKey:       attribute.StringValue("example.com"),
+		semconv.ServerPortKey:          attribute.IntValue(8443),
+		semconv.HTTPRequestBodySizeKey: attribute.IntValue(4),
+		semconv.UserAgentOriginalKey:   attribute.StringValue("test"),
+	}
+	for k, v := range want {
+		if got, ok := got.Value(k); !ok || got != v {
+			t.Errorf("%s = %v, want %v", k, got.Emit(), v.Emit())
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	if set := attribute.NewSet(RequestAttributes(req, "")...); set.HasValue(semconv.HTTPRouteKey) {
+		t.Error("empty route is added")
+	}
+}
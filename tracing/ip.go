@@ -92,7 +92,7 @@ func XRealIp() IpHeader {
 }
 func RemoteAddr() IpHeader {
 	return func(req *http.Request) (string, bool) {
-		if ip, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr)); err != nil && ip != "" {
+		if ip, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr)); err == nil && ip != "" {
 			return ip, true
 		}
 		return "", false
@@ -1,6 +1,7 @@
 package tracing
 
 import (
+	"net/http"
 	"testing"
 )
 
@@ -69,3 +70,24 @@ func TestIpHeaders_Append(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteAddr(t *testing.T) {
+	tests := []struct {
+		remoteAddr string
+		want       string
+		ok         bool
+	}{
+		{remoteAddr: "192.0.2.1:1234", want: "192.0.2.1", ok: true},
+		{remoteAddr: "[2001:db8::1]:443", want: "2001:db8::1", ok: true},
+		{remoteAddr: "192.0.2.1"},
+		{remoteAddr: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.remoteAddr, func(t *testing.T) {
+			req := &http.Request{RemoteAddr: tt.remoteAddr}
+			if got, ok := RemoteAddr()(req); got != tt.want || ok != tt.ok {
+				t.Errorf("RemoteAddr() = %s, %v, want %s, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// StatusClassKey is the attribute of the response status class such as "2xx".
+const StatusClassKey = attribute.Key("http.response.status_class")
+
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// ServerMetrics records the HTTP server metrics of the OpenTelemetry semantic conventions.
+// Durations and body sizes are keyed by route, method and status class,
+// and active requests by method.
+type ServerMetrics struct {
+	duration     metric.Float64Histogram
+	active       metric.Int64UpDownCounter
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+}
+
+// NewServerMetrics creates the instruments with the meter of scope from mp.
+// A nil mp uses the global provider.
+func NewServerMetrics(mp metric.MeterProvider, scope string) (*ServerMetrics, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(scope)
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+	if err != nil {
+		return nil, err
+	}
+	active, err := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Number of active HTTP server requests."))
+	if err != nil {
+		return nil, err
+	}
+	requestSize, err := meter.Int64Histogram("http.server.request.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server request bodies."))
+	if err != nil {
+		return nil, err
+	}
+	responseSize, err := meter.Int64Histogram("http.server.response.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server response bodies."))
+	if err != nil {
+		return nil, err
+	}
+	return &ServerMetrics{
+		duration:     duration,
+		active:       active,
+		requestSize:  requestSize,
+		responseSize: responseSize,
+	}, nil
+}
+
+// Begin counts an active request and returns the function to record it when it is done.
+// An empty route is recorded without http.route, so that raw paths do not increase the cardinality.
+// A negative body size is unknown and not recorded.
+func (m *ServerMetrics) Begin(ctx context.Context, method string) func(route string, status int, requestSize, responseSize int64) {
+	start := time.Now()
+	methodAttr := semconv.HTTPRequestMethodKey.String(requestMethod(method))
+	m.active.Add(ctx, 1, metric.WithAttributes(methodAttr))
+	return func(route string, status int, requestSize, responseSize int64) {
+		m.active.Add(ctx, -1, metric.WithAttributes(methodAttr))
+		attrs := make([]attribute.KeyValue, 0, 3)
+		attrs = append(attrs, methodAttr, StatusClassKey.String(StatusClass(status)))
+		if route != "" {
+			attrs = append(attrs, semconv.HTTPRoute(route))
+		}
+		set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+		m.duration.Record(ctx, time.Since(start).Seconds(), set)
+		if requestSize >= 0 {
+			m.requestSize.Record(ctx, requestSize, set)
+		}
+		if responseSize >= 0 {
+			m.responseSize.Record(ctx, responseSize, set)
+		}
+	}
+}
+
+// StatusClass returns the class of the status code such as "2xx".
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+func requestMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goccha/envar"
+	"github.com/goccha/logging/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// MeterBuilder builds a MeterProvider with the exporters selected by environment variables.
+//
+//	METRICS_ENABLE                       builds the provider when true
+//	OTEL_METRICS_EXPORTER                comma separated list of otlp, prometheus or none (default otlp)
+//	OTEL_EXPORTER_OTLP_METRICS_PROTOCOL  grpc or http/protobuf (default grpc)
+//	OTEL_METRIC_EXPORT_INTERVAL          export interval of otlp in milliseconds
+//
+// When prometheus is selected, Prometheus is set to the handler serving the text format.
+type MeterBuilder struct {
+	Resource   *resource.Resource
+	Options    []sdkmetric.Option
+	Prometheus *PrometheusExporter
+	tracer     *tracing.TracerBuilder
+}
+
+func NewMeter() *MeterBuilder {
+	return &MeterBuilder{
+		Options: make([]sdkmetric.Option, 0, 4),
+	}
+}
+
+// WithResource sets the resource of the meter provider.
+func (b *MeterBuilder) WithResource(res *resource.Resource) *MeterBuilder {
+	b.Resource, b.tracer = res, nil
+	return b
+}
+
+// WithTracerResource shares the resource of the tracer provider built by tb.
+// The resource is resolved by tracing.TracerBuilder.ResolveResource when Build is called.
+func (b *MeterBuilder) WithTracerResource(tb *tracing.TracerBuilder) *MeterBuilder {
+	b.Resource, b.tracer = nil, tb
+	return b
+}
+
+func (b *MeterBuilder) WithOptions(opts ...sdkmetric.Option) *MeterBuilder {
+	if len(opts) > 0 {
+		b.Options = append(b.Options, opts...)
+	}
+	return b
+}
+
+func (b *MeterBuilder) Build(ctx context.Context) (*sdkmetric.MeterProvider, error) {
+	if !envar.Bool("METRICS_ENABLE") {
+		return nil, nil
+	}
+	options := make([]MeterProviderOption, 0, 2)
+	for _, name := range strings.Split(envar.Get("OTEL_METRICS_EXPORTER").String("otlp"), ",") {
+		switch strings.TrimSpace(name) {
+		case "otlp":
+			switch protocol := envar.Get("OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL").String("grpc"); protocol {
+			case "grpc":
+				options = append(options, WithGrpcExporter())
+			case "http/protobuf", "http":
+				options = append(options, WithHttpExporter())
+			default:
+				return nil, fmt.Errorf("unsupported otlp metrics protocol: %s", protocol)
+			}
+		case "prometheus":
+			if b.Prometheus == nil {
+				p, err := NewPrometheusExporter()
+				if err != nil {
+					return nil, err
+				}
+				b.Prometheus = p
+			}
+			options = append(options, WithPrometheusExporter(b.Prometheus))
+		case "none", "":
+		default:
+			return nil, fmt.Errorf("unsupported metrics exporter: %s", name)
+		}
+	}
+	opts, err := MeterProviderOptions(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	res := b.Resource
+	if res == nil && b.tracer != nil {
+		res = b.tracer.ResolveResource()
+	}
+	if res != nil {
+		opts = append(opts, sdkmetric.WithResource(res))
+	}
+	return NewMeterProvider(ctx, append(opts, b.Options...)...)
+}
+
+// NewMeterProvider creates a MeterProvider and sets it as the global provider.
+func NewMeterProvider(ctx context.Context, opts ...sdkmetric.Option) (*sdkmetric.MeterProvider, error) {
+	mp := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(mp)
+	return mp, nil
+}
+
+type MeterProviderOption func(ctx context.Context) (sdkmetric.Option, error)
+
+func MeterProviderOptions(ctx context.Context, options ...MeterProviderOption) ([]sdkmetric.Option, error) {
+	opts := make([]sdkmetric.Option, 0, len(options))
+	for _, option := range options {
+		opt, err := option(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
+
+func WithGrpcExporter(opts ...otlpmetricgrpc.Option) MeterProviderOption {
+	return func(ctx context.Context) (sdkmetric.Option, error) {
+		endpoint := envar.Get("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT").String("0.0.0.0:4317")
+		options := make([]otlpmetricgrpc.Option, 0, len(opts)+2)
+		options = append(options, otlpmetricgrpc.WithInsecure(), otlpmetricgrpc.WithEndpoint(endpoint))
+		options = append(options, opts...)
+		exporter, err := otlpmetricgrpc.New(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)), nil
+	}
+}
+
+func WithHttpExporter(opts ...otlpmetrichttp.Option) MeterProviderOption {
+	return func(ctx context.Context) (sdkmetric.Option, error) {
+		endpoint := envar.Get("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT").String("0.0.0.0:4318")
+		options := make([]otlpmetrichttp.Option, 0, len(opts)+2)
+		options = append(options, otlpmetrichttp.WithInsecure(), otlpmetrichttp.WithEndpoint(endpoint))
+		options = append(options, opts...)
+		exporter, err := otlpmetrichttp.New(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)), nil
+	}
+}
+
+// WithPrometheusExporter reads the metrics when p is scraped.
+func WithPrometheusExporter(p *PrometheusExporter) MeterProviderOption {
+	return func(ctx context.Context) (sdkmetric.Option, error) {
+		return sdkmetric.WithReader(p.reader), nil
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccha/logging/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestMeterBuilder_Build(t *testing.T) {
+	t.Setenv("METRICS_ENABLE", "true")
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	defer otel.SetMeterProvider(otel.GetMeterProvider())
+
+	tb := tracing.NewTracer().WithResource(resource.NewSchemaless(semconv.ServiceName("meter-test")))
+	b := NewMeter().WithTracerResource(tb)
+	ctx := context.Background()
+	mp, err := b.Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = mp.Shutdown(ctx) }()
+	if b.Prometheus == nil {
+		t.Fatal("prometheus exporter is not set")
+	}
+	counter, _ := otel.GetMeterProvider().Meter("test").Int64Counter("jobs.done")
+	counter.Add(ctx, 1)
+
+	rec := httptest.NewRecorder()
+	b.Prometheus.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{`target_info{service_name="meter-test"`, `jobs_done_total{otel_scope_name="test"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("%s is not found in\n%s", want, body)
+		}
+	}
+}
+
+func TestMeterBuilder_Build_Disabled(t *testing.T) {
+	t.Setenv("METRICS_ENABLE", "false")
+	if mp, err := NewMeter().Build(context.Background()); mp != nil || err != nil {
+		t.Errorf("Build() = %v, %v", mp, err)
+	}
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+)
+
+// PrometheusExporter is a reader of the MeterProvider that serves the metrics
+// in the Prometheus exposition format with go.opentelemetry.io/otel/exporters/prometheus.
+// The metrics are registered to a registry of their own, labeled with the instrumentation scope.
+type PrometheusExporter struct {
+	reader  *otelprom.Exporter
+	handler http.Handler
+}
+
+func NewPrometheusExporter(opts ...otelprom.Option) (*PrometheusExporter, error) {
+	registry := prometheus.NewRegistry()
+	options := make([]otelprom.Option, 0, len(opts)+1)
+	options = append(options, otelprom.WithRegisterer(registry))
+	options = append(options, opts...)
+	reader, err := otelprom.New(options...)
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusExporter{
+		reader:  reader,
+		handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+	}, nil
+}
+
+func (p *PrometheusExporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	p.handler.ServeHTTP(w, req)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheusExporter(t *testing.T) {
+	p, err := NewPrometheusExporter(otelprom.WithoutTargetInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(p.reader))
+	ctx := context.Background()
+	for _, scope := range []string{"a", "b"} {
+		counter, _ := mp.Meter(scope).Int64Counter("jobs.done", metric.WithDescription("Done jobs."))
+		counter.Add(ctx, 3, metric.WithAttributes(attribute.String("queue", `a"b`)))
+	}
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	want := `# HELP jobs_done_total Done jobs.
+# TYPE jobs_done_total counter
+jobs_done_total{otel_scope_name="a",otel_scope_schema_url="",otel_scope_version="",queue="a\"b"} 3
+jobs_done_total{otel_scope_name="b",otel_scope_schema_url="",otel_scope_version="",queue="a\"b"} 3
+`
+	if got := string(body); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
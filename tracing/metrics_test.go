@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestServerMetrics_Begin(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	m, err := NewServerMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	m.Begin(ctx, http.MethodGet)("", http.StatusNotFound, -1, 9)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]uint64)
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		switch data := metric.Data.(type) {
+		case metricdata.Histogram[int64]:
+			for _, p := range data.DataPoints {
+				counts[metric.Name] += p.Count
+			}
+		case metricdata.Histogram[float64]:
+			for _, p := range data.DataPoints {
+				counts[metric.Name] += p.Count
+				if p.Attributes.HasValue(semconv.HTTPRouteKey) {
+					t.Error("empty route is recorded as http.route")
+				}
+			}
+		}
+	}
+	if counts["http.server.request.duration"] != 1 || counts["http.server.response.body.size"] != 1 ||
+		counts["http.server.request.body.size"] != 0 {
+		t.Errorf("counts = %v", counts)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	for status, want := range map[int]string{200: "2xx", 404: "4xx", 503: "5xx", 0: "other"} {
+		if got := StatusClass(status); got != want {
+			t.Errorf("StatusClass(%d) = %s, want %s", status, got, want)
+		}
+	}
+	if !strings.HasPrefix(requestMethod("PURGE"), "_") {
+		t.Error("unknown methods must be _OTHER")
+	}
+}
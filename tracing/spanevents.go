@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanEventSink records log events as events of the recording span in the context.
+// For ERROR and above it also sets the status of the span to Error and records an exception.
+// It implements log.Sink and is enabled with log.WithSink:
+//
+//	log.SetDefault(log.DefaultLogger().With(log.WithSink(tracing.SpanEvents())))
+type SpanEventSink struct {
+	levels map[zerolog.Level]bool
+}
+
+// SpanEvents returns a SpanEventSink for the given levels.
+// Without levels, WARNING and above are recorded.
+func SpanEvents(levels ...zerolog.Level) *SpanEventSink {
+	if len(levels) == 0 {
+		levels = []zerolog.Level{zerolog.WarnLevel, zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel}
+	}
+	s := &SpanEventSink{levels: make(map[zerolog.Level]bool, len(levels))}
+	for _, l := range levels {
+		s.levels[l] = true
+	}
+	return s
+}
+
+func (s *SpanEventSink) WriteEvent(ctx context.Context, level zerolog.Level, p []byte) error {
+	if !s.levels[level] {
+		return nil
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return nil
+	}
+	fields := make(map[string]any)
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	if err := d.Decode(&fields); err != nil {
+		return err
+	}
+	msg, _ := fields[zerolog.MessageFieldName].(string)
+	severity, _ := fields["severity"].(string)
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	for k, v := range fields {
+		switch k {
+		case zerolog.MessageFieldName, zerolog.TimestampFieldName, zerolog.LevelFieldName:
+			continue
+		}
+		attrs = append(attrs, attributeOf(k, v))
+	}
+	name := msg
+	if name == "" {
+		name = "log"
+	}
+	span.AddEvent(name, trace.WithAttributes(attrs...))
+	if level >= zerolog.ErrorLevel {
+		errMsg := msg
+		if v, ok := fields[zerolog.ErrorFieldName].(string); ok && v != "" {
+			errMsg = v
+		}
+		exception := []attribute.KeyValue{semconv.ExceptionMessage(errMsg), semconv.ExceptionType(severity)}
+		if v, ok := fields[zerolog.ErrorStackFieldName].(string); ok {
+			exception = append(exception, semconv.ExceptionStacktrace(v))
+		}
+		span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(exception...))
+		span.SetStatus(codes.Error, errMsg)
+	}
+	return nil
+}
+
+func attributeOf(k string, v any) attribute.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(k, v)
+	case bool:
+		return attribute.Bool(k, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return attribute.Int64(k, i)
+		}
+		f, _ := v.Float64()
+		return attribute.Float64(k, f)
+	default:
+		data, _ := json.Marshal(v)
+		return attribute.String(k, string(data))
+	}
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanEventSink(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []zerolog.Level
+		level  zerolog.Level
+		event  string
+		events int
+		status codes.Code
+	}{
+		{name: "info is not bridged", level: zerolog.InfoLevel, event: `{"severity":"INFO","message":"info"}`},
+		{name: "warn", level: zerolog.WarnLevel, event: `{"severity":"WARNING","user":"test","message":"warn"}`, events: 1},
+		{name: "error", level: zerolog.ErrorLevel, event: `{"severity":"ERROR","error":"failed","message":"error"}`, events: 2, status: codes.Error},
+		{name: "configured info", levels: []zerolog.Level{zerolog.InfoLevel}, level: zerolog.InfoLevel, event: `{"severity":"INFO","message":"info"}`, events: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+			if err := SpanEvents(tt.levels...).WriteEvent(ctx, tt.level, []byte(tt.event)); err != nil {
+				t.Fatal(err)
+			}
+			span.End()
+			ended := sr.Ended()[0]
+			if len(ended.Events()) != tt.events {
+				t.Errorf("events = %v, want %d", ended.Events(), tt.events)
+			}
+			if ended.Status().Code != tt.status {
+				t.Errorf("status = %v, want %v", ended.Status().Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package tracelog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"net/http"
+	"time"
+
+	"github.com/goccha/http-constants/pkg/headers"
+	"github.com/goccha/logging/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIdGenerator generates a request ID for a request without one.
+type RequestIdGenerator func(ctx context.Context, req *http.Request) string
+
+// WithRequestIdGenerator sets the generator used when the request has no request ID.
+// It applies to the tracing contexts that read TraceConfig: this package, extensions/cloudtrace,
+// extensions/datadog and extensions/xray. The deprecated extensions/aws and extensions/gcp
+// and extensions/tracers have their own configuration and do not generate request IDs.
+// By default no request ID is generated.
+func WithRequestIdGenerator(g RequestIdGenerator) Option {
+	return func(c *Config) {
+		c.RequestIdGenerator = g
+	}
+}
+
+// UUIDv4 generates a random UUID.
+func UUIDv4() RequestIdGenerator {
+	return func(ctx context.Context, req *http.Request) string {
+		return uuid.NewString()
+	}
+}
+
+// UUIDv7 generates a time-ordered UUID.
+func UUIDv7() RequestIdGenerator {
+	return func(ctx context.Context, req *http.Request) string {
+		if id, err := uuid.NewV7(); err == nil {
+			return id.String()
+		}
+		return uuid.NewString()
+	}
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates a ULID, a time-ordered ID of 26 characters.
+func ULID() RequestIdGenerator {
+	return func(ctx context.Context, req *http.Request) string {
+		return newULID(time.Now())
+	}
+}
+
+func newULID(t time.Time) string {
+	var b [16]byte
+	ms := uint64(t.UnixMilli())
+	b[0], b[1] = byte(ms>>40), byte(ms>>32)
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	_, _ = rand.Read(b[6:])
+	// 128 bits are encoded in 26 characters of 5 bits, the first one holding only 3 bits.
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var s [26]byte
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// FromTraceId uses the trace ID of the span in the context.
+// Without a valid span, the request ID is generated by fallback.
+func FromTraceId(fallback RequestIdGenerator) RequestIdGenerator {
+	return func(ctx context.Context, req *http.Request) string {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			return sc.TraceID().String()
+		}
+		if fallback != nil {
+			return fallback(ctx, req)
+		}
+		return ""
+	}
+}
+
+// RequestIdHeaderName returns the header in which the request ID is sent.
+func (c *Config) RequestIdHeaderName() string {
+	if c.RequestIdHeader != "" {
+		return c.RequestIdHeader
+	}
+	return headers.RequestID
+}
+
+// RequestId returns the request ID of the tracing context stored in ctx.
+// The tracing context must implement GetRequestId() string.
+func RequestId(ctx context.Context) string {
+	if value := tracing.Value(ctx); value != nil {
+		if tc, ok := value.(interface{ GetRequestId() string }); ok {
+			return tc.GetRequestId()
+		}
+	}
+	return ""
+}
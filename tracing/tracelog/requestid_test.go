@@ -0,0 +1,64 @@
+package tracelog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestConfig_GetRequestId(t *testing.T) {
+	traceId, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceId,
+		SpanID:  trace.SpanID{1},
+	}))
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		header string
+		gen    RequestIdGenerator
+		want   string
+	}{
+		{name: "header", ctx: context.Background(), header: "abc", gen: UUIDv4(), want: "^abc$"},
+		{name: "none", ctx: context.Background(), want: "^$"},
+		{name: "uuidv4", ctx: context.Background(), gen: UUIDv4(), want: "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"},
+		{name: "uuidv7", ctx: context.Background(), gen: UUIDv7(), want: "^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"},
+		{name: "ulid", ctx: context.Background(), gen: ULID(), want: "^[0-7][0-9A-HJKMNP-TV-Z]{25}$"},
+		{name: "trace id", ctx: spanCtx, gen: FromTraceId(ULID()), want: "^0af7651916cd43dd8448eb211c80319c$"},
+		{name: "trace id fallback", ctx: context.Background(), gen: FromTraceId(ULID()), want: "^[0-7][0-9A-HJKMNP-TV-Z]{25}$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			c := &Config{RequestIdGenerator: tt.gen}
+			if got := c.GetRequestId(tt.ctx, req); !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Errorf("GetRequestId() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_GetRequestId_Header(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Correlation-ID", "abc")
+	c := &Config{RequestIdHeader: "X-Correlation-ID"}
+	if got := c.GetRequestId(context.Background(), req); got != "abc" {
+		t.Errorf("GetRequestId() = %s, want abc", got)
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	now := time.UnixMilli(1469918176385)
+	// The first 10 characters encode the timestamp.
+	if got := newULID(now)[:10]; got != "01ARYZ6S41" {
+		t.Errorf("newULID() = %s", got)
+	}
+}
@@ -19,6 +19,7 @@ const (
 type Config struct {
 	RequestIdHeader string
 	RequestIdFunc
+	RequestIdGenerator
 	tracing.NewFunc
 }
 
@@ -27,13 +28,18 @@ type RequestIdFunc func(ctx context.Context, req *http.Request) string
 var _config = &Config{}
 
 func (c *Config) GetRequestId(ctx context.Context, req *http.Request) string {
+	var id string
 	if c.RequestIdFunc != nil {
-		return c.RequestIdFunc(ctx, req)
+		id = c.RequestIdFunc(ctx, req)
+	} else if c.RequestIdHeader != "" {
+		id = req.Header.Get(c.RequestIdHeader)
+	} else {
+		id = req.Header.Get(headers.RequestID)
 	}
-	if c.RequestIdHeader != "" {
-		return req.Header.Get(_config.RequestIdHeader)
+	if id == "" && c.RequestIdGenerator != nil {
+		id = c.RequestIdGenerator(ctx, req)
 	}
-	return req.Header.Get(headers.RequestID)
+	return id
 }
 
 func TraceConfig() *Config {
@@ -121,6 +127,10 @@ type TracingContext struct {
 	Service   string
 }
 
+func (tc *TracingContext) GetRequestId() string {
+	return tc.RequestID
+}
+
 func (tc *TracingContext) Dump(ctx context.Context, log *zerolog.Event) *zerolog.Event {
 	span := trace.SpanFromContext(ctx)
 	if span != nil {
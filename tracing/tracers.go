@@ -17,6 +17,7 @@ import (
 
 type TracerBuilder struct {
 	Propagator propagation.TextMapPropagator
+	Resource   *resource.Resource
 	Options    []sdktrace.TracerProviderOption
 }
 
@@ -32,6 +33,13 @@ func (b *TracerBuilder) WithPropagator(propagator propagation.TextMapPropagator)
 	}
 	return b
 }
+
+// WithResource sets the resource of the tracer provider.
+// The same resource can be shared with the logger and meter providers.
+func (b *TracerBuilder) WithResource(res *resource.Resource) *TracerBuilder {
+	b.Resource = res
+	return b
+}
 func (b *TracerBuilder) WithOptions(opts ...sdktrace.TracerProviderOption) *TracerBuilder {
 	if len(opts) > 0 {
 		b.Options = append(b.Options, opts...)
@@ -39,7 +47,18 @@ func (b *TracerBuilder) WithOptions(opts ...sdktrace.TracerProviderOption) *Trac
 	return b
 }
 func (b *TracerBuilder) Build(ctx context.Context) (*sdktrace.TracerProvider, error) {
-	return NewTracerProvider(ctx, b.Propagator, b.Options...)
+	opts := append([]sdktrace.TracerProviderOption{sdktrace.WithResource(b.ResolveResource())}, b.Options...)
+	return NewTracerProvider(ctx, b.Propagator, opts...)
+}
+
+// ResolveResource returns the resource set with WithResource and stores it in Resource.
+// Without it, it is resource.Default() as in the SDK.
+// A resource given to Options with sdktrace.WithResource is not shared; set it with WithResource instead.
+func (b *TracerBuilder) ResolveResource() *resource.Resource {
+	if b.Resource == nil {
+		b.Resource = resource.Default()
+	}
+	return b.Resource
 }
 
 func NewTracerProvider(ctx context.Context, propagator propagation.TextMapPropagator, opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
@@ -0,0 +1,31 @@
+package tracing
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestTracerBuilder_ResolveResource(t *testing.T) {
+	def, _ := resource.Default().Set().Value(semconv.ServiceNameKey)
+	tests := []struct {
+		name    string
+		builder *TracerBuilder
+		want    string
+	}{
+		{name: "builder", builder: NewTracer().WithResource(resource.NewSchemaless(semconv.ServiceName("from-builder"))), want: "from-builder"},
+		{name: "default", builder: NewTracer(), want: def.AsString()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.builder.ResolveResource()
+			if v, _ := res.Set().Value(semconv.ServiceNameKey); v.AsString() != tt.want {
+				t.Errorf("service.name = %s, want %s", v.AsString(), tt.want)
+			}
+			if tt.builder.Resource != res || tt.builder.ResolveResource() != res {
+				t.Error("resolved resource is not kept")
+			}
+		})
+	}
+}